@@ -0,0 +1,149 @@
+package anomaly
+
+import (
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/peternabil/go-api/models"
+)
+
+const (
+	// Threshold is the robust z-score above which an amount is unusual.
+	Threshold = 3.5
+	// minSamples is how many earlier amounts a category needs before its
+	// amounts are judged at all.
+	minSamples = 5
+	// minHistory is how many earlier transactions a user needs before a
+	// category they have never used counts as unexpected.
+	minHistory = 20
+	// minMAD keeps categories whose amounts never vary from flagging every
+	// small change.
+	minMAD = 0.1
+	// Window is how many of the latest amounts of a category are kept to
+	// judge the next one by.
+	Window = 100
+
+	ReasonAmount   = "unusual amount"
+	ReasonCategory = "unexpected category"
+)
+
+// Anomaly explains why a transaction looks unusual. Score is the robust
+// z-score of its log amount and Typical the median amount of its category.
+type Anomaly struct {
+	Reasons []string
+	Score   float64
+	Typical int64
+}
+
+type series struct {
+	categoryID uuid.UUID
+	negative   bool
+}
+
+// window holds the latest log amounts of a series both in the order they
+// were added and sorted, so that medians need no sorting.
+type window struct {
+	added  []float64
+	sorted []float64
+}
+
+func (w *window) add(v float64) {
+	i := sort.SearchFloat64s(w.sorted, v)
+	w.sorted = append(w.sorted, 0)
+	copy(w.sorted[i+1:], w.sorted[i:])
+	w.sorted[i] = v
+	w.added = append(w.added, v)
+	if len(w.added) > Window {
+		old := w.added[0]
+		w.added = w.added[1:]
+		j := sort.SearchFloat64s(w.sorted, old)
+		w.sorted = append(w.sorted[:j], w.sorted[j+1:]...)
+	}
+}
+
+// Detector scores transactions against a user's earlier ones, comparing
+// log amounts to the median and median absolute deviation (MAD) of the same
+// category and sign.
+type Detector struct {
+	amounts    map[series]*window
+	categories map[uuid.UUID]int
+	total      int
+}
+
+// New builds a detector from the user's transaction history.
+func New(history []models.Transaction) *Detector {
+	d := &Detector{amounts: map[series]*window{}, categories: map[uuid.UUID]int{}}
+	for _, t := range history {
+		d.Add(t)
+	}
+	return d
+}
+
+func logAmount(amount int) float64 {
+	if amount < 0 {
+		amount = -amount
+	}
+	return math.Log1p(float64(amount))
+}
+
+// median returns the median of values sorted ascending.
+func median(sorted []float64) float64 {
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// deviations returns the absolute deviations of sorted values from m in
+// ascending order. They grow walking away from m on either side, so the two
+// sides only need merging.
+func deviations(sorted []float64, m float64) []float64 {
+	out := make([]float64, 0, len(sorted))
+	right := sort.SearchFloat64s(sorted, m)
+	left := right - 1
+	for left >= 0 || right < len(sorted) {
+		if right >= len(sorted) || (left >= 0 && m-sorted[left] <= sorted[right]-m) {
+			out = append(out, m-sorted[left])
+			left--
+		} else {
+			out = append(out, sorted[right]-m)
+			right++
+		}
+	}
+	return out
+}
+
+// Add makes the transaction part of the history later ones are judged by.
+func (d *Detector) Add(t models.Transaction) {
+	s := series{t.CategoryID, t.Negative}
+	if d.amounts[s] == nil {
+		d.amounts[s] = &window{}
+	}
+	d.amounts[s].add(logAmount(t.Amount))
+	d.categories[t.CategoryID]++
+	d.total++
+}
+
+// Check returns why the transaction is unusual, or nil if it is not.
+func (d *Detector) Check(t models.Transaction) *Anomaly {
+	a := &Anomaly{Reasons: []string{}}
+	if d.categories[t.CategoryID] == 0 && d.total >= minHistory {
+		a.Reasons = append(a.Reasons, ReasonCategory)
+	}
+	w := d.amounts[series{t.CategoryID, t.Negative}]
+	if w != nil && len(w.sorted) >= minSamples {
+		m := median(w.sorted)
+		mad := math.Max(median(deviations(w.sorted, m)), minMAD)
+		a.Score = math.Round(0.6745*(logAmount(t.Amount)-m)/mad*100) / 100
+		a.Typical = int64(math.Round(math.Expm1(m)))
+		if math.Abs(a.Score) > Threshold {
+			a.Reasons = append(a.Reasons, ReasonAmount)
+		}
+	}
+	if len(a.Reasons) == 0 {
+		return nil
+	}
+	return a
+}
@@ -0,0 +1,105 @@
+package anomaly
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/peternabil/go-api/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMedian(t *testing.T) {
+	testCases := []struct {
+		name   string
+		values []float64
+		want   float64
+	}{
+		{name: "odd", values: []float64{1, 2, 9}, want: 2},
+		{name: "even", values: []float64{1, 2, 4, 9}, want: 3},
+		{name: "ties", values: []float64{2, 2, 2, 5}, want: 2},
+		{name: "ties across the middle", values: []float64{1, 3, 3, 3, 3, 7}, want: 3},
+		{name: "single", values: []float64{4}, want: 4},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, median(tt.values))
+		})
+	}
+}
+
+func TestDeviations(t *testing.T) {
+	testCases := []struct {
+		name   string
+		sorted []float64
+	}{
+		{name: "spread", sorted: []float64{1, 2, 4, 8, 16}},
+		{name: "ties", sorted: []float64{3, 3, 3, 3}},
+		{name: "one side", sorted: []float64{1, 1, 1, 10}},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			m := median(tt.sorted)
+			want := []float64{}
+			for _, v := range tt.sorted {
+				want = append(want, math.Abs(v-m))
+			}
+			sort.Float64s(want)
+			require.Equal(t, want, deviations(tt.sorted, m))
+		})
+	}
+}
+
+func TestWindow(t *testing.T) {
+	w := &window{}
+	for i := 0; i < Window+10; i++ {
+		w.add(float64(Window + 10 - i))
+	}
+	require.Len(t, w.added, Window)
+	require.Len(t, w.sorted, Window)
+	require.True(t, sort.Float64sAreSorted(w.sorted))
+	// the 10 largest values were added first and have been evicted
+	require.Equal(t, float64(Window), w.sorted[Window-1])
+	require.Equal(t, 1.0, w.sorted[0])
+}
+
+func TestCheck(t *testing.T) {
+	groceries := uuid.New()
+	jewelry := uuid.New()
+	spending := func(category uuid.UUID, amounts ...int) []models.Transaction {
+		transactions := []models.Transaction{}
+		for _, amount := range amounts {
+			transactions = append(transactions, models.Transaction{CategoryID: category, Amount: amount, Negative: true})
+		}
+		return transactions
+	}
+	usual := spending(groceries, 90, 100, 110, 95, 105, 100)
+	many := spending(groceries, 90, 100, 110, 95, 105, 100, 90, 100, 110, 95, 105, 100, 90, 100, 110, 95, 105, 100, 90, 100)
+	testCases := []struct {
+		name        string
+		history     []models.Transaction
+		transaction models.Transaction
+		reasons     []string
+	}{
+		{name: "empty history", history: nil, transaction: spending(groceries, 5000)[0]},
+		{name: "too few samples", history: spending(groceries, 100, 100, 100), transaction: spending(groceries, 5000)[0]},
+		{name: "usual amount", history: usual, transaction: spending(groceries, 102)[0]},
+		{name: "unusual amount", history: usual, transaction: spending(groceries, 400)[0], reasons: []string{ReasonAmount}},
+		{name: "identical amounts", history: spending(groceries, 100, 100, 100, 100, 100), transaction: spending(groceries, 101)[0]},
+		{name: "other sign", history: usual, transaction: models.Transaction{CategoryID: groceries, Amount: 400}},
+		{name: "new category with short history", history: usual, transaction: spending(jewelry, 100)[0]},
+		{name: "new category", history: many, transaction: spending(jewelry, 100)[0], reasons: []string{ReasonCategory}},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			a := New(tt.history).Check(tt.transaction)
+			if tt.reasons == nil {
+				require.Nil(t, a)
+				return
+			}
+			require.NotNil(t, a)
+			require.Equal(t, tt.reasons, a.Reasons)
+		})
+	}
+}
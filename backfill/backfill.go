@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/peternabil/go-api/intitializers"
+	"github.com/peternabil/go-api/models"
+)
+
+func init() {
+	intitializers.LoadEnvVariables()
+	intitializers.LoadDB()
+}
+
+// backfill rebuilds the daily rollups of one user, or of every user when no
+// -user is given. With -check it only reports where the rollups and the
+// transactions disagree and exits non-zero if they do anywhere.
+func main() {
+	userFlag := flag.String("user", "", "uid of the user to backfill (all users by default)")
+	check := flag.Bool("check", false, "only compare the rollups to the transactions")
+	flag.Parse()
+
+	store := intitializers.NewMainStore(intitializers.DB)
+	users := []models.User{}
+	if *userFlag != "" {
+		id, err := uuid.Parse(*userFlag)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		users = append(users, models.User{UID: id})
+	} else {
+		all, err := store.GetUsers()
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		users = all
+	}
+	failed := false
+	for _, user := range users {
+		if *check {
+			mismatches, err := store.CheckRollups(user.UID)
+			if err != nil {
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+			for _, m := range mismatches {
+				fmt.Printf("%s %s category %s priority %s negative %t: raw %d/%d, rollup %d/%d\n",
+					user.UID, m.Day.Format("2006-01-02"), m.CategoryID, m.PriorityID, m.Negative, m.RawTotal, m.RawCount, m.RollupTotal, m.RollupCount)
+			}
+			failed = failed || len(mismatches) > 0
+			continue
+		}
+		if err := store.RebuildRollups(user.UID); err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+		fmt.Printf("rebuilt rollups of %s\n", user.UID)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
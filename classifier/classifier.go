@@ -0,0 +1,281 @@
+package classifier
+
+import (
+	"container/list"
+	"math"
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/peternabil/go-api/models"
+)
+
+// Suggestion is a label ranked by the classifier together with its
+// normalized probability.
+type Suggestion struct {
+	ID         uuid.UUID
+	Confidence float64
+}
+
+// Features turns a title and an amount into the tokens the classifier is
+// trained on: lower-cased words of the title and a log2 bucket of the amount.
+func Features(title string, amount int) []string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	features := make([]string, 0, len(words)+1)
+	for _, w := range words {
+		if len(w) > 1 {
+			features = append(features, "w:"+w)
+		}
+	}
+	if amount < 0 {
+		amount = -amount
+	}
+	features = append(features, "amt:"+strconv.Itoa(bits.Len(uint(amount))))
+	return features
+}
+
+type label struct {
+	docs   int
+	total  int
+	counts map[string]int
+}
+
+// model is a multinomial naive Bayes model with Laplace smoothing.
+type model struct {
+	docs   int
+	labels map[uuid.UUID]*label
+	vocab  map[string]int
+}
+
+func newModel() *model {
+	return &model{labels: map[uuid.UUID]*label{}, vocab: map[string]int{}}
+}
+
+func (m *model) add(id uuid.UUID, features []string, delta int) {
+	l, ok := m.labels[id]
+	if !ok {
+		l = &label{counts: map[string]int{}}
+		m.labels[id] = l
+	}
+	m.docs += delta
+	l.docs += delta
+	for _, f := range features {
+		l.counts[f] += delta
+		l.total += delta
+		m.vocab[f] += delta
+		if l.counts[f] <= 0 {
+			delete(l.counts, f)
+		}
+		if m.vocab[f] <= 0 {
+			delete(m.vocab, f)
+		}
+	}
+	if l.docs <= 0 {
+		delete(m.labels, id)
+	}
+}
+
+func (m *model) predict(features []string) []Suggestion {
+	if m.docs == 0 {
+		return []Suggestion{}
+	}
+	vocab := float64(len(m.vocab) + 1)
+	scores := make([]Suggestion, 0, len(m.labels))
+	max := math.Inf(-1)
+	for id, l := range m.labels {
+		score := math.Log(float64(l.docs+1) / float64(m.docs+len(m.labels)))
+		for _, f := range features {
+			score += math.Log(float64(l.counts[f]+1) / (float64(l.total) + vocab))
+		}
+		if score > max {
+			max = score
+		}
+		scores = append(scores, Suggestion{ID: id, Confidence: score})
+	}
+	var sum float64
+	for i := range scores {
+		scores[i].Confidence = math.Exp(scores[i].Confidence - max)
+		sum += scores[i].Confidence
+	}
+	for i := range scores {
+		scores[i].Confidence /= sum
+	}
+	sort.Slice(scores, func(i, j int) bool {
+		return scores[i].Confidence > scores[j].Confidence
+	})
+	return scores
+}
+
+type document struct {
+	categoryID uuid.UUID
+	priorityID uuid.UUID
+	features   []string
+}
+
+// Classifier predicts the category and priority of a transaction for a
+// single user.
+type Classifier struct {
+	categories *model
+	priorities *model
+	docs       map[uuid.UUID]document
+}
+
+// New trains a classifier on the given transactions.
+func New(transactions []models.Transaction) *Classifier {
+	c := &Classifier{categories: newModel(), priorities: newModel(), docs: map[uuid.UUID]document{}}
+	for _, t := range transactions {
+		c.learn(t)
+	}
+	return c
+}
+
+func (c *Classifier) learn(t models.Transaction) {
+	c.forget(t.ID)
+	doc := document{categoryID: t.CategoryID, priorityID: t.PriorityID, features: Features(t.Title, t.Amount)}
+	c.categories.add(doc.categoryID, doc.features, 1)
+	c.priorities.add(doc.priorityID, doc.features, 1)
+	c.docs[t.ID] = doc
+}
+
+func (c *Classifier) forget(id uuid.UUID) bool {
+	doc, ok := c.docs[id]
+	if !ok {
+		return false
+	}
+	c.categories.add(doc.categoryID, doc.features, -1)
+	c.priorities.add(doc.priorityID, doc.features, -1)
+	delete(c.docs, id)
+	return true
+}
+
+// Suggest returns category and priority suggestions ordered by confidence.
+func (c *Classifier) Suggest(title string, amount int) ([]Suggestion, []Suggestion) {
+	features := Features(title, amount)
+	return c.categories.predict(features), c.priorities.predict(features)
+}
+
+// MaxUsers is how many users' classifiers a cache keeps trained. Beyond it
+// the least recently used one is dropped and retrained on next use.
+const MaxUsers = 1000
+
+// entry is the classifier of one user. ready is closed once it is trained or
+// its training failed with err; changes that arrive while it trains are kept
+// in pending and replayed on it afterwards.
+type entry struct {
+	ready   chan struct{}
+	cl      *Classifier
+	err     error
+	pending []func(*Classifier)
+	elem    *list.Element
+}
+
+// Cache keeps one lazily trained classifier per user and updates it as
+// transactions change. Training happens outside the lock, once per user
+// however many requests wait for it.
+type Cache struct {
+	mu       sync.Mutex
+	users    map[uuid.UUID]*entry
+	recent   *list.List
+	capacity int
+}
+
+// NewCache creates an empty classifier cache holding at most MaxUsers
+// classifiers.
+func NewCache() *Cache {
+	return &Cache{users: map[uuid.UUID]*entry{}, recent: list.New(), capacity: MaxUsers}
+}
+
+// drop removes the user's entry, if it is still e, from the cache.
+func (c *Cache) drop(id uuid.UUID, e *entry) {
+	if c.users[id] == e {
+		delete(c.users, id)
+		c.recent.Remove(e.elem)
+	}
+}
+
+// Suggest trains the user's classifier with load on first use and returns
+// its suggestions for the given title and amount. A failed load is reported
+// to every request waiting on it and retried by the next one.
+func (c *Cache) Suggest(id uuid.UUID, title string, amount int, load func() ([]models.Transaction, error)) ([]Suggestion, []Suggestion, error) {
+	c.mu.Lock()
+	e, ok := c.users[id]
+	if ok {
+		c.recent.MoveToFront(e.elem)
+		c.mu.Unlock()
+	} else {
+		e = &entry{ready: make(chan struct{})}
+		e.elem = c.recent.PushFront(id)
+		c.users[id] = e
+		if c.recent.Len() > c.capacity {
+			oldest := c.recent.Back()
+			c.drop(oldest.Value.(uuid.UUID), c.users[oldest.Value.(uuid.UUID)])
+		}
+		c.mu.Unlock()
+		transactions, err := load()
+		c.mu.Lock()
+		if err != nil {
+			e.err = err
+			c.drop(id, e)
+		} else {
+			e.cl = New(transactions)
+			for _, change := range e.pending {
+				change(e.cl)
+			}
+		}
+		e.pending = nil
+		close(e.ready)
+		c.mu.Unlock()
+	}
+	<-e.ready
+	if e.err != nil {
+		return nil, nil, e.err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	categories, priorities := e.cl.Suggest(title, amount)
+	return categories, priorities, nil
+}
+
+// Learn adds or replaces the transaction in its owner's classifier if that
+// classifier has already been trained or is being trained.
+func (c *Cache) Learn(t models.Transaction) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.users[t.UserID]
+	if !ok {
+		return
+	}
+	if e.cl == nil {
+		e.pending = append(e.pending, func(cl *Classifier) { cl.learn(t) })
+		return
+	}
+	e.cl.learn(t)
+}
+
+// Forget removes the transaction from whichever classifier learned it.
+func (c *Cache) Forget(id uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, e := range c.users {
+		if e.cl == nil {
+			e.pending = append(e.pending, func(cl *Classifier) { cl.forget(id) })
+		} else if e.cl.forget(id) {
+			return
+		}
+	}
+}
+
+// Reset drops the user's classifier so that it is retrained on next use.
+func (c *Cache) Reset(id uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.users[id]; ok {
+		c.drop(id, e)
+	}
+}
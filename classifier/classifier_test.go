@@ -0,0 +1,174 @@
+package classifier
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/peternabil/go-api/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatures(t *testing.T) {
+	testCases := []struct {
+		name   string
+		title  string
+		amount int
+		want   []string
+	}{
+		{name: "words and amount", title: "Uber Trip", amount: 20, want: []string{"w:uber", "w:trip", "amt:5"}},
+		{name: "punctuation and case", title: "STARBUCKS #42, Downtown", amount: 7, want: []string{"w:starbucks", "w:42", "w:downtown", "amt:3"}},
+		{name: "single letters dropped", title: "a b coffee", amount: 1, want: []string{"w:coffee", "amt:1"}},
+		{name: "negative amount", title: "refund", amount: -20, want: []string{"w:refund", "amt:5"}},
+		{name: "empty title", title: "", amount: 0, want: []string{"amt:0"}},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Features(tt.title, tt.amount))
+		})
+	}
+}
+
+func TestPredict(t *testing.T) {
+	food, transport := uuid.New(), uuid.New()
+	m := newModel()
+	require.Empty(t, m.predict(Features("uber", 20)))
+
+	m.add(food, Features("grocery market", 80), 1)
+	m.add(food, Features("market", 60), 1)
+	m.add(transport, Features("uber trip", 20), 1)
+	testCases := []struct {
+		name   string
+		title  string
+		amount int
+		want   uuid.UUID
+	}{
+		{name: "seen word", title: "uber", amount: 20, want: transport},
+		{name: "seen words", title: "market", amount: 70, want: food},
+		{name: "amount decides for unseen words", title: "payment", amount: 80, want: food},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			suggestions := m.predict(Features(tt.title, tt.amount))
+			require.Len(t, suggestions, 2)
+			require.Equal(t, tt.want, suggestions[0].ID)
+			require.GreaterOrEqual(t, suggestions[0].Confidence, suggestions[1].Confidence)
+			require.InDelta(t, 1, suggestions[0].Confidence+suggestions[1].Confidence, 1e-9)
+		})
+	}
+}
+
+func TestLearnForget(t *testing.T) {
+	food, transport, priority := uuid.New(), uuid.New(), uuid.New()
+	ride := models.Transaction{ID: uuid.New(), Title: "uber trip", Amount: 20, CategoryID: transport, PriorityID: priority}
+	c := New([]models.Transaction{
+		{ID: uuid.New(), Title: "market", Amount: 60, CategoryID: food, PriorityID: priority},
+		ride,
+	})
+	categories, _ := c.Suggest("uber", 20)
+	require.Equal(t, transport, categories[0].ID)
+
+	// learning a transaction again replaces it instead of counting it twice
+	ride.CategoryID = food
+	c.learn(ride)
+	require.Len(t, c.docs, 2)
+	require.Equal(t, 2, c.categories.docs)
+	require.NotContains(t, c.categories.labels, transport)
+	categories, _ = c.Suggest("uber", 20)
+	require.Len(t, categories, 1)
+	require.Equal(t, food, categories[0].ID)
+
+	require.True(t, c.forget(ride.ID))
+	require.False(t, c.forget(ride.ID))
+	require.Equal(t, 1, c.categories.docs)
+	require.NotContains(t, c.categories.vocab, "w:uber")
+
+	c = New(nil)
+	require.False(t, c.forget(ride.ID))
+	categories, priorities := c.Suggest("uber", 20)
+	require.Empty(t, categories)
+	require.Empty(t, priorities)
+}
+
+func TestCache(t *testing.T) {
+	food := uuid.New()
+	user := uuid.New()
+	history := []models.Transaction{{ID: uuid.New(), Title: "market", Amount: 60, CategoryID: food, UserID: user}}
+
+	t.Run("loads once for concurrent requests", func(t *testing.T) {
+		cache := NewCache()
+		var loads int32
+		release := make(chan struct{})
+		load := func() ([]models.Transaction, error) {
+			atomic.AddInt32(&loads, 1)
+			<-release
+			return history, nil
+		}
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				categories, _, err := cache.Suggest(user, "market", 60, load)
+				require.NoError(t, err)
+				require.Equal(t, food, categories[0].ID)
+			}()
+		}
+		// other users are not held up by the load
+		_, _, err := cache.Suggest(uuid.New(), "market", 60, func() ([]models.Transaction, error) {
+			return nil, nil
+		})
+		require.NoError(t, err)
+		close(release)
+		wg.Wait()
+		require.Equal(t, int32(1), loads)
+	})
+
+	t.Run("learns while loading", func(t *testing.T) {
+		cache := NewCache()
+		transport := uuid.New()
+		ride := models.Transaction{ID: uuid.New(), Title: "uber", Amount: 20, CategoryID: transport, UserID: user}
+		categories, _, err := cache.Suggest(user, "uber", 20, func() ([]models.Transaction, error) {
+			cache.Learn(ride)
+			return history, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, transport, categories[0].ID)
+	})
+
+	t.Run("retries after a failed load", func(t *testing.T) {
+		cache := NewCache()
+		_, _, err := cache.Suggest(user, "market", 60, func() ([]models.Transaction, error) {
+			return nil, errors.New("db error")
+		})
+		require.Error(t, err)
+		categories, _, err := cache.Suggest(user, "market", 60, func() ([]models.Transaction, error) {
+			return history, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, food, categories[0].ID)
+	})
+
+	t.Run("drops the least recently used", func(t *testing.T) {
+		cache := NewCache()
+		cache.capacity = 2
+		loads := map[uuid.UUID]int{}
+		load := func(id uuid.UUID) func() ([]models.Transaction, error) {
+			return func() ([]models.Transaction, error) {
+				loads[id]++
+				return history, nil
+			}
+		}
+		first, second, third := uuid.New(), uuid.New(), uuid.New()
+		for _, id := range []uuid.UUID{first, second, first, third, first, second} {
+			_, _, err := cache.Suggest(id, "market", 60, load(id))
+			require.NoError(t, err)
+		}
+		require.Len(t, cache.users, 2)
+		require.Equal(t, 1, loads[first])
+		require.Equal(t, 2, loads[second])
+		require.Equal(t, 1, loads[third])
+	})
+}
@@ -2,10 +2,12 @@ package controllers
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/peternabil/go-api/models"
 )
 
 func setNegative(c *gin.Context, negative *bool) error {
@@ -20,8 +22,70 @@ func setNegative(c *gin.Context, negative *bool) error {
 	return nil
 }
 
+// setSides reads the negative query parameter, which may also be "both" to
+// ask for income and expenses side by side.
+func setSides(c *gin.Context, negative, both *bool) error {
+	if c.Query("negative") == "both" {
+		*both = true
+		return nil
+	}
+	return setNegative(c, negative)
+}
+
+func setDepth(c *gin.Context, depth *int) error {
+	var err error
+	*depth, err = strconv.Atoi(c.DefaultQuery("depth", "0"))
+	if err == nil && *depth < 0 {
+		err = fmt.Errorf("depth must not be negative")
+	}
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return err
+	}
+	return nil
+}
+
+// loadLocation looks up an IANA time zone name; the empty name is UTC.
+func loadLocation(name string) (*time.Location, error) {
+	if name == "Local" {
+		return nil, fmt.Errorf("unknown time zone %s", name)
+	}
+	return time.LoadLocation(name)
+}
+
+// userLocation returns the time zone the request's dates are read and
+// bucketed in: the tz query parameter, else the user's preference, else UTC.
+func userLocation(c *gin.Context) (*time.Location, error) {
+	name := c.Query("tz")
+	if name == "" {
+		if user, ok := c.Value("user").(models.User); ok {
+			name = user.TimeZone
+		}
+	}
+	return loadLocation(name)
+}
+
+// requestLocation is userLocation responding with 400 on an unknown zone.
+func requestLocation(c *gin.Context) (*time.Location, error) {
+	loc, err := userLocation(c)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return nil, err
+	}
+	return loc, nil
+}
+
+// setDates reads the period from the range preset or from start_date and
+// end_date.
 func setDates(c *gin.Context, startDate, endDate *time.Time) error {
-	startDateVal, endDateVal, startDateErr, endDateErr := getDates(c.Request)
+	loc, err := requestLocation(c)
+	if err != nil {
+		return err
+	}
+	if c.Query("range") != "" {
+		return setRange(c, loc, startDate, endDate)
+	}
+	startDateVal, endDateVal, startDateErr, endDateErr := getDates(c.Request, loc)
 	if startDateErr != nil {
 		c.JSON(400, gin.H{"error": startDateErr.Error()})
 		return startDateErr
@@ -35,6 +99,8 @@ func setDates(c *gin.Context, startDate, endDate *time.Time) error {
 	return nil
 }
 
+// GetDailyValues returns spending between the dates as a continuous series
+// of day, week, month, quarter or year buckets.
 func (server *Server) GetDailyValues(c *gin.Context) {
 	var startDate time.Time
 	var endDate time.Time
@@ -47,6 +113,15 @@ func (server *Server) GetDailyValues(c *gin.Context) {
 	if err != nil {
 		return
 	}
+	var b bucketer
+	err = setBucketer(c, &b, granularityDay)
+	if err != nil {
+		return
+	}
+	err = checkBuckets(c, b, startDate, endDate)
+	if err != nil {
+		return
+	}
 	user := server.store.GetUserFromToken(c)
 	spendings, err := server.store.GetTransactionsDateRangeGroupByDay(user.UID, startDate, endDate, negative)
 	if err != nil {
@@ -54,46 +129,154 @@ func (server *Server) GetDailyValues(c *gin.Context) {
 		return
 	}
 	fmt.Println(spendings)
-	c.JSON(200, gin.H{"spending": spendings})
+	c.JSON(200, gin.H{"start_date": startDate, "end_date": endDate, "spending": fillBuckets(spendings, startDate, endDate, negative, b)})
 }
 
 func (server *Server) GetHighestCategory(c *gin.Context) {
 	var startDate time.Time
 	var endDate time.Time
-	var negative bool
+	var negative, both bool
 	err := setDates(c, &startDate, &endDate)
 	if err != nil {
 		return
 	}
-	err = setNegative(c, &negative)
+	err = setSides(c, &negative, &both)
+	if err != nil {
+		return
+	}
+	var depth int
+	err = setDepth(c, &depth)
 	if err != nil {
 		return
 	}
 	user := server.store.GetUserFromToken(c)
-	spendings, err := server.store.GetHighestSpendingCategory(user.UID, startDate, endDate, negative)
+	if both {
+		expenses, err := server.store.GetHighestSpendingCategory(user.UID, startDate, endDate, true, depth)
+		if err != nil {
+			c.Status(500)
+			return
+		}
+		income, err := server.store.GetHighestSpendingCategory(user.UID, startDate, endDate, false, depth)
+		if err != nil {
+			c.Status(500)
+			return
+		}
+		c.JSON(200, gin.H{"start_date": startDate, "end_date": endDate, "spending": categoryFlows(expenses, income)})
+		return
+	}
+	spendings, err := server.store.GetHighestSpendingCategory(user.UID, startDate, endDate, negative, depth)
 	if err != nil {
 		c.Status(500)
 		return
 	}
-	c.JSON(200, gin.H{"spending": spendings})
+	c.JSON(200, gin.H{"start_date": startDate, "end_date": endDate, "spending": spendings})
 }
 func (server *Server) GetHighestPriority(c *gin.Context) {
 	var startDate time.Time
 	var endDate time.Time
-	var negative bool
+	var negative, both bool
 	err := setDates(c, &startDate, &endDate)
 	if err != nil {
 		return
 	}
-	err = setNegative(c, &negative)
+	err = setSides(c, &negative, &both)
 	if err != nil {
 		return
 	}
 	user := server.store.GetUserFromToken(c)
+	if both {
+		expenses, err := server.store.GetHighestSpendingPriority(user.UID, startDate, endDate, true)
+		if err != nil {
+			c.Status(500)
+			return
+		}
+		income, err := server.store.GetHighestSpendingPriority(user.UID, startDate, endDate, false)
+		if err != nil {
+			c.Status(500)
+			return
+		}
+		c.JSON(200, gin.H{"start_date": startDate, "end_date": endDate, "spending": priorityFlows(expenses, income)})
+		return
+	}
 	spendings, err := server.store.GetHighestSpendingPriority(user.UID, startDate, endDate, negative)
 	if err != nil {
 		c.Status(500)
 		return
 	}
-	c.JSON(200, gin.H{"spending": spendings})
+	c.JSON(200, gin.H{"start_date": startDate, "end_date": endDate, "spending": spendings})
+}
+
+// GetCashFlow returns income, expenses, their net and the running net for
+// every bucket between the dates.
+func (server *Server) GetCashFlow(c *gin.Context) {
+	var startDate time.Time
+	var endDate time.Time
+	err := setDates(c, &startDate, &endDate)
+	if err != nil {
+		return
+	}
+	var b bucketer
+	err = setBucketer(c, &b, granularityDay)
+	if err != nil {
+		return
+	}
+	err = checkBuckets(c, b, startDate, endDate)
+	if err != nil {
+		return
+	}
+	user := server.store.GetUserFromToken(c)
+	expenses, err := server.store.GetTransactionsDateRangeGroupByDay(user.UID, startDate, endDate, true)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	income, err := server.store.GetTransactionsDateRangeGroupByDay(user.UID, startDate, endDate, false)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	expenseBuckets := fillBuckets(expenses, startDate, endDate, true, b)
+	incomeBuckets := fillBuckets(income, startDate, endDate, false, b)
+	type cashFlow struct {
+		Date          time.Time
+		Label         string
+		Income        int64
+		Expense       int64
+		Net           int64
+		CumulativeNet int64
+	}
+	flows := make([]cashFlow, len(expenseBuckets))
+	var cumulative int64
+	for i := range expenseBuckets {
+		net := incomeBuckets[i].Total - expenseBuckets[i].Total
+		cumulative += net
+		flows[i] = cashFlow{
+			Date:          expenseBuckets[i].Date,
+			Label:         expenseBuckets[i].Label,
+			Income:        incomeBuckets[i].Total,
+			Expense:       expenseBuckets[i].Total,
+			Net:           net,
+			CumulativeNet: cumulative,
+		}
+	}
+	c.JSON(200, gin.H{"start_date": startDate, "end_date": endDate, "cash_flow": flows})
+}
+
+// GetSummary returns the dashboard overview of the period in one call.
+func (server *Server) GetSummary(c *gin.Context) {
+	var startDate time.Time
+	var endDate time.Time
+	err := setDates(c, &startDate, &endDate)
+	if err != nil {
+		return
+	}
+	user := server.store.GetUserFromToken(c)
+	summary, err := server.store.TotalSpending(user.UID, startDate, endDate)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	days := math.Max(math.Ceil(endDate.Sub(startDate).Hours()/24), 1)
+	summary.AverageDailySpend = math.Round(float64(summary.Expense)/days*100) / 100
+	c.JSON(200, gin.H{"start_date": startDate, "end_date": endDate, "summary": summary})
 }
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -106,6 +107,151 @@ func TestGetDailyValues(t *testing.T) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 		},
+		{
+			name:  "monthly buckets",
+			param: "?end_date=2023-12-19T16:23:25.742Z&start_date=2023-09-19T16:21:53.561Z&negative=true&granularity=month",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetTransactionsDateRangeGroupByDay(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return([]models.Spending{
+					{Date: time.Date(2023, 9, 20, 0, 0, 0, 0, time.UTC), Total: 100, Negative: true},
+					{Date: time.Date(2023, 9, 28, 0, 0, 0, 0, time.UTC), Total: 50, Negative: true},
+					{Date: time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC), Total: 70, Negative: true},
+				}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					Spending []spendingBucket `json:"spending"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Len(t, res.Spending, 4)
+				require.Equal(t, "2023-09", res.Spending[0].Label)
+				require.Equal(t, int64(150), res.Spending[0].Total)
+				require.Equal(t, int64(0), res.Spending[1].Total)
+				require.Equal(t, int64(0), res.Spending[2].Total)
+				require.Equal(t, int64(70), res.Spending[3].Total)
+			},
+		},
+		{
+			name:  "time zone override",
+			param: "?start_date=2023-11-01T00:00:00&end_date=2023-11-02T23:59:59&negative=true&tz=Asia/Tokyo",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetTransactionsDateRangeGroupByDay(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(id uuid.UUID, startDate, endDate time.Time, negative bool) ([]models.Spending, error) {
+						require.Equal(t, "Asia/Tokyo", startDate.Location().String())
+						require.Equal(t, time.Date(2023, 10, 31, 15, 0, 0, 0, time.UTC), startDate.UTC())
+						return []models.Spending{{Date: time.Date(2023, 11, 2, 0, 0, 0, 0, time.UTC), Total: 30, Negative: true}}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					Spending []spendingBucket `json:"spending"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Len(t, res.Spending, 2)
+				require.Equal(t, "2023-11-01", res.Spending[0].Label)
+				require.Equal(t, int64(30), res.Spending[1].Total)
+			},
+		},
+		{
+			name:  "user time zone with offset dates",
+			param: "?start_date=2023-11-01T04:00:00Z&end_date=2023-11-02T23:59:59-04:00&negative=true",
+			buildStubs: func(store *mock_store.MockStore) {
+				newYorker := user
+				newYorker.TimeZone = "America/New_York"
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(newYorker, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(newYorker)
+				store.EXPECT().
+					GetTransactionsDateRangeGroupByDay(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(id uuid.UUID, startDate, endDate time.Time, negative bool) ([]models.Spending, error) {
+						require.Equal(t, "America/New_York", startDate.Location().String())
+						require.Equal(t, 0, startDate.Hour())
+						require.Equal(t, 23, endDate.Hour())
+						return []models.Spending{}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					Spending []spendingBucket `json:"spending"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Len(t, res.Spending, 2)
+				require.Equal(t, "2023-11-01", res.Spending[0].Label)
+				require.Equal(t, "2023-11-02", res.Spending[1].Label)
+			},
+		},
+		{
+			name:  "unknown time zone",
+			param: "?start_date=2023-11-01T00:00:00Z&end_date=2023-11-02T23:59:59Z&negative=true&tz=Mars/Olympus",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "weeks starting on sunday",
+			param: "?end_date=2023-11-30T00:00:00Z&start_date=2023-11-15T00:00:00Z&negative=true&granularity=week&week_start=sunday",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetTransactionsDateRangeGroupByDay(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return([]models.Spending{
+					{Date: time.Date(2023, 11, 18, 0, 0, 0, 0, time.UTC), Total: 10, Negative: true},
+					{Date: time.Date(2023, 11, 19, 0, 0, 0, 0, time.UTC), Total: 20, Negative: true},
+				}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					Spending []spendingBucket `json:"spending"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Len(t, res.Spending, 3)
+				require.Equal(t, time.Sunday, res.Spending[0].Date.Weekday())
+				require.Equal(t, int64(10), res.Spending[0].Total)
+				require.Equal(t, int64(20), res.Spending[1].Total)
+			},
+		},
+		{
+			name:  "too many buckets",
+			param: "?end_date=2023-12-31T00:00:00Z&start_date=2000-01-01T00:00:00Z&negative=true&granularity=day",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "wrong granularity",
+			param: "?end_date=2023-12-19T16:23:25.742Z&start_date=2023-11-19T16:21:53.561Z&negative=true&granularity=hour",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name:  "error reading token",
 			param: "?end_date=2023-12-19T16:23:25.742Z&start_date=2023-11-19T16:21:53.561Z&negative=true",
@@ -171,12 +317,67 @@ func TestGetHighestCategory(t *testing.T) {
 				store.EXPECT().
 					GetUserFromToken(gomock.Any()).Times(1).Return(user)
 				store.EXPECT().
-					GetHighestSpendingCategory(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(spendings, nil)
+					GetHighestSpendingCategory(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(spendings, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "both sides",
+			param: "?end_date=2023-12-19T16:23:25.742Z&start_date=2023-11-19T16:21:53.561Z&negative=both",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetHighestSpendingCategory(gomock.Any(), gomock.Any(), gomock.Any(), true, 0).Times(1).Return(spendings, nil)
+				store.EXPECT().
+					GetHighestSpendingCategory(gomock.Any(), gomock.Any(), gomock.Any(), false, 0).Times(1).Return([]models.SpendingCategory{
+					{Total: 250, CategoryID: spendings[0].CategoryID},
+					{Total: 40, CategoryID: uuid.New()},
+				}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					Spending []categoryFlow `json:"spending"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Len(t, res.Spending, 2)
+				require.Equal(t, int64(100), res.Spending[0].Expense)
+				require.Equal(t, int64(250), res.Spending[0].Income)
+				require.Equal(t, int64(150), res.Spending[0].Net)
+				require.Equal(t, int64(40), res.Spending[1].Net)
+			},
+		},
+		{
+			name:  "roll up to depth",
+			param: "?end_date=2023-12-19T16:23:25.742Z&start_date=2023-11-19T16:21:53.561Z&negative=true&depth=1",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetHighestSpendingCategory(gomock.Any(), gomock.Any(), gomock.Any(), true, 1).Times(1).Return(spendings, nil)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 			},
 		},
+		{
+			name:  "wrong depth param",
+			param: "?end_date=2023-12-19T16:23:25.742Z&start_date=2023-11-19T16:21:53.561Z&negative=true&depth=-1",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name:  "wrong negative param",
 			param: "?end_date=2023-12-19T16:23:25.742Z&start_date=2023-11-19T16:21:53.561Z&negative=test",
@@ -219,7 +420,7 @@ func TestGetHighestCategory(t *testing.T) {
 				store.EXPECT().
 					GetUserFromToken(gomock.Any()).Times(1).Return(user)
 				store.EXPECT().
-					GetHighestSpendingCategory(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(spendings, errors.New("db error"))
+					GetHighestSpendingCategory(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(spendings, errors.New("db error"))
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
@@ -354,3 +555,272 @@ func TestGetHighestPriority(t *testing.T) {
 		})
 	}
 }
+
+func TestGetCashFlow(t *testing.T) {
+	password := "Password123"
+	encryptedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
+	user := models.User{
+		UID:       uuid.New(),
+		Email:     "user@test.com",
+		FirstName: "test",
+		LastName:  "user",
+		Password:  string(encryptedPass),
+	}
+	testCases := []struct {
+		name          string
+		param         string
+		buildStubs    func(store *mock_store.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "success",
+			param: "?end_date=2023-12-31T00:00:00Z&start_date=2023-10-01T00:00:00Z&granularity=month",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetTransactionsDateRangeGroupByDay(user.UID, gomock.Any(), gomock.Any(), true).Times(1).Return([]models.Spending{
+					{Date: time.Date(2023, 10, 5, 0, 0, 0, 0, time.UTC), Total: 300, Negative: true},
+					{Date: time.Date(2023, 12, 5, 0, 0, 0, 0, time.UTC), Total: 900, Negative: true},
+				}, nil)
+				store.EXPECT().
+					GetTransactionsDateRangeGroupByDay(user.UID, gomock.Any(), gomock.Any(), false).Times(1).Return([]models.Spending{
+					{Date: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), Total: 1000},
+					{Date: time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC), Total: 1000},
+				}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					CashFlow []struct {
+						Label         string
+						Income        int64
+						Expense       int64
+						Net           int64
+						CumulativeNet int64
+					} `json:"cash_flow"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Len(t, res.CashFlow, 3)
+				require.Equal(t, "2023-10", res.CashFlow[0].Label)
+				require.Equal(t, int64(700), res.CashFlow[0].Net)
+				require.Equal(t, int64(1000), res.CashFlow[1].Net)
+				require.Equal(t, int64(-900), res.CashFlow[2].Net)
+				require.Equal(t, int64(800), res.CashFlow[2].CumulativeNet)
+			},
+		},
+		{
+			name:  "too many buckets",
+			param: "?end_date=2023-12-31T00:00:00Z&start_date=2000-01-01T00:00:00Z&granularity=day",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "wrong start date format",
+			param: "?end_date=2023-12-31T00:00:00Z&start_date=2023-10-01",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "db error",
+			param: "?end_date=2023-12-31T00:00:00Z&start_date=2023-10-01T00:00:00Z",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetTransactionsDateRangeGroupByDay(user.UID, gomock.Any(), gomock.Any(), true).Times(1).Return(nil, errors.New("db error"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockStore := mock_store.NewMockStore(mockCtrl)
+			tt.buildStubs(mockStore)
+
+			server, _ := NewServer(mockStore, nil)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest("GET", fmt.Sprintf("/smart-account/api/v1/cash-flow%s", tt.param), nil)
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(recorder)
+		})
+	}
+}
+
+func TestGetSummary(t *testing.T) {
+	password := "Password123"
+	encryptedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
+	user := models.User{
+		UID:       uuid.New(),
+		Email:     "user@test.com",
+		FirstName: "test",
+		LastName:  "user",
+		Password:  string(encryptedPass),
+	}
+	summary := models.Summary{
+		Income:        3000,
+		Expense:       1500,
+		Net:           1500,
+		Count:         12,
+		TopCategories: []models.SpendingCategory{{CategoryID: uuid.New(), Cname: "Rent", Total: 1000, Negative: true}},
+		TopPriorities: []models.SpendingPriority{{PriorityID: uuid.New(), Pname: "Essential", Total: 1200, Negative: true}},
+	}
+	testCases := []struct {
+		name          string
+		param         string
+		buildStubs    func(store *mock_store.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "success",
+			param: "?start_date=2023-11-01T00:00:00Z&end_date=2023-12-01T00:00:00Z",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					TotalSpending(user.UID, time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)).Times(1).Return(summary, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					Summary models.Summary `json:"summary"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, int64(1500), res.Summary.Net)
+				require.Equal(t, 50.0, res.Summary.AverageDailySpend)
+				require.Len(t, res.Summary.TopCategories, 1)
+				require.Nil(t, res.Summary.LargestTransaction)
+			},
+		},
+		{
+			name:  "named range",
+			param: "?range=last_30_days&tz=Asia/Tokyo",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					TotalSpending(user.UID, gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(id uuid.UUID, startDate, endDate time.Time) (models.Summary, error) {
+						today := time.Now().In(startDate.Location())
+						require.Equal(t, "Asia/Tokyo", startDate.Location().String())
+						require.Equal(t, time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, startDate.Location()).AddDate(0, 0, -29), startDate)
+						require.Equal(t, startDate.AddDate(0, 0, 30).Add(-time.Second), endDate)
+						return summary, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					StartDate time.Time `json:"start_date"`
+					EndDate   time.Time `json:"end_date"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, 0, res.StartDate.Hour())
+				require.Equal(t, 23, res.EndDate.Hour())
+			},
+		},
+		{
+			name:  "fiscal month",
+			param: "?range=last_month",
+			buildStubs: func(store *mock_store.MockStore) {
+				payday := user
+				payday.FiscalMonthStart = 25
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(payday, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(payday)
+				store.EXPECT().
+					TotalSpending(user.UID, gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(id uuid.UUID, startDate, endDate time.Time) (models.Summary, error) {
+						require.Equal(t, 25, startDate.Day())
+						require.Equal(t, 24, endDate.Day())
+						require.Equal(t, startDate.AddDate(0, 1, 0).Add(-time.Second), endDate)
+						require.True(t, endDate.Before(time.Now()))
+						return summary, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "unknown range",
+			param: "?range=last_fortnight",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "wrong end date format",
+			param: "?start_date=2023-11-01T00:00:00Z&end_date=2023-12-01",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "db error",
+			param: "?start_date=2023-11-01T00:00:00Z&end_date=2023-12-01T00:00:00Z",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					TotalSpending(user.UID, gomock.Any(), gomock.Any()).Times(1).Return(models.Summary{}, errors.New("db error"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockStore := mock_store.NewMockStore(mockCtrl)
+			tt.buildStubs(mockStore)
+
+			server, _ := NewServer(mockStore, nil)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest("GET", fmt.Sprintf("/smart-account/api/v1/summary%s", tt.param), nil)
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/peternabil/go-api/anomaly"
+	"github.com/peternabil/go-api/models"
+)
+
+const (
+	// anomalyLookbackMonths is how far back history goes when judging a
+	// transaction.
+	anomalyLookbackMonths = 12
+	// anomalyHistoryLimit caps the history loaded to judge a new transaction.
+	anomalyHistoryLimit = 500
+	// anomalyMaxPeriodMonths caps the period anomalies are listed for.
+	anomalyMaxPeriodMonths = 12
+)
+
+// GetAnomalies lists the unusual transactions between the dates, at most
+// anomalyMaxPeriodMonths apart, judging each one against what the user
+// recorded in the anomalyLookbackMonths before it.
+func (server *Server) GetAnomalies(c *gin.Context) {
+	var startDate time.Time
+	var endDate time.Time
+	err := setDates(c, &startDate, &endDate)
+	if err != nil {
+		return
+	}
+	if endDate.After(startDate.AddDate(0, anomalyMaxPeriodMonths, 0)) {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("the period can span at most %d months", anomalyMaxPeriodMonths)})
+		return
+	}
+	user := server.store.GetUserFromToken(c)
+	transactions, err := server.store.GetTransactionsDateRange(user.UID, startDate.AddDate(0, -anomalyLookbackMonths, 0), endDate)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	type flagged struct {
+		Transaction models.Transaction
+		Anomaly     *anomaly.Anomaly
+	}
+	anomalies := []flagged{}
+	detector := anomaly.New(nil)
+	for _, t := range transactions {
+		if !t.CreatedAt.Before(startDate) {
+			if a := detector.Check(t); a != nil {
+				anomalies = append(anomalies, flagged{Transaction: t, Anomaly: a})
+			}
+		}
+		detector.Add(t)
+	}
+	c.JSON(200, gin.H{"start_date": startDate, "end_date": endDate, "anomalies": anomalies})
+}
+
+// checkAnomaly judges a newly created transaction against the user's recent
+// history. Scoring never holds up the write: without history it reports
+// nothing.
+func (server *Server) checkAnomaly(transaction models.Transaction) *anomaly.Anomaly {
+	since := transaction.CreatedAt.AddDate(0, -anomalyLookbackMonths, 0)
+	history, err := server.store.GetRecentTransactions(transaction.UserID, since, anomalyHistoryLimit)
+	if err != nil {
+		log.Printf("anomaly history for user %s: %v", transaction.UserID, err)
+		return nil
+	}
+	detector := anomaly.New(nil)
+	for _, t := range history {
+		if t.ID != transaction.ID {
+			detector.Add(t)
+		}
+	}
+	return detector.Check(transaction)
+}
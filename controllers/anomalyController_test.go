@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/peternabil/go-api/anomaly"
+	mock_store "github.com/peternabil/go-api/mocks"
+	"github.com/peternabil/go-api/models"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestGetAnomalies(t *testing.T) {
+	password := "Password123"
+	encryptedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
+	user := models.User{
+		UID:       uuid.New(),
+		Email:     "user@test.com",
+		FirstName: "test",
+		LastName:  "user",
+		Password:  string(encryptedPass),
+	}
+	groceries := uuid.New()
+	jewelry := uuid.New()
+	transaction := func(title string, category uuid.UUID, amount int, date time.Time) models.Transaction {
+		t := models.Transaction{ID: uuid.New(), Title: title, CategoryID: category, Amount: amount, Negative: true, UserID: user.UID}
+		t.CreatedAt = date
+		return t
+	}
+	transactions := []models.Transaction{}
+	for i := 0; i < 25; i++ {
+		transactions = append(transactions, transaction("market", groceries, 80+i%5*10, time.Date(2023, 10, 1+i, 0, 0, 0, 0, time.UTC)))
+	}
+	bigBill := transaction("market", groceries, 300, time.Date(2023, 11, 3, 0, 0, 0, 0, time.UTC))
+	ring := transaction("ring", jewelry, 90, time.Date(2023, 11, 4, 0, 0, 0, 0, time.UTC))
+	transactions = append(transactions,
+		transaction("market", groceries, 95, time.Date(2023, 11, 2, 0, 0, 0, 0, time.UTC)),
+		bigBill,
+		ring,
+	)
+	testCases := []struct {
+		name          string
+		param         string
+		buildStubs    func(store *mock_store.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "success",
+			param: "?start_date=2023-11-01T00:00:00Z&end_date=2023-11-30T00:00:00Z",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetTransactionsDateRange(user.UID, time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC), gomock.Any()).Times(1).Return(transactions, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					Anomalies []struct {
+						Transaction models.Transaction
+						Anomaly     anomaly.Anomaly
+					} `json:"anomalies"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Len(t, res.Anomalies, 2)
+				require.Equal(t, bigBill.ID, res.Anomalies[0].Transaction.ID)
+				require.Equal(t, []string{anomaly.ReasonAmount}, res.Anomalies[0].Anomaly.Reasons)
+				require.Greater(t, res.Anomalies[0].Anomaly.Score, anomaly.Threshold)
+				require.Equal(t, ring.ID, res.Anomalies[1].Transaction.ID)
+				require.Equal(t, []string{anomaly.ReasonCategory}, res.Anomalies[1].Anomaly.Reasons)
+			},
+		},
+		{
+			name:  "wrong date format",
+			param: "?start_date=2023-11-01&end_date=2023-11-30T00:00:00Z",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "period too long",
+			param: "?start_date=2000-01-01T00:00:00Z&end_date=2023-11-30T00:00:00Z",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "db error",
+			param: "?start_date=2023-11-01T00:00:00Z&end_date=2023-11-30T00:00:00Z",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetTransactionsDateRange(user.UID, gomock.Any(), gomock.Any()).Times(1).Return(nil, errors.New("db error"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockStore := mock_store.NewMockStore(mockCtrl)
+			tt.buildStubs(mockStore)
+
+			server, _ := NewServer(mockStore, nil)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest("GET", fmt.Sprintf("/smart-account/api/v1/anomalies%s", tt.param), nil)
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(recorder)
+		})
+	}
+}
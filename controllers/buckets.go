@@ -0,0 +1,238 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/peternabil/go-api/models"
+)
+
+const (
+	granularityDay     = "day"
+	granularityWeek    = "week"
+	granularityMonth   = "month"
+	granularityQuarter = "quarter"
+	granularityYear    = "year"
+
+	// maxBuckets caps how many buckets one series can be split into.
+	maxBuckets = 5000
+)
+
+// bucketer splits time into consecutive buckets of one granularity. Weeks
+// start on weekStart; with a Monday start they are ISO weeks.
+type bucketer struct {
+	granularity string
+	weekStart   time.Weekday
+}
+
+// start returns the first instant of the bucket containing t.
+func (b bucketer) start(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch b.granularity {
+	case granularityWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) - int(b.weekStart) + 7) % 7))
+	case granularityMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	case granularityQuarter:
+		return time.Date(t.Year(), t.Month()-(t.Month()-1)%3, 1, 0, 0, 0, 0, t.Location())
+	case granularityYear:
+		return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return day
+	}
+}
+
+// next returns the start of the bucket following the one starting at t.
+func (b bucketer) next(t time.Time) time.Time {
+	switch b.granularity {
+	case granularityWeek:
+		return t.AddDate(0, 0, 7)
+	case granularityMonth:
+		return t.AddDate(0, 1, 0)
+	case granularityQuarter:
+		return t.AddDate(0, 3, 0)
+	case granularityYear:
+		return t.AddDate(1, 0, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+// label names the bucket starting at t, e.g. "2023-W47" or "2023-Q4".
+func (b bucketer) label(t time.Time) string {
+	switch b.granularity {
+	case granularityWeek:
+		if b.weekStart == time.Monday {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}
+		return t.Format("2006-01-02")
+	case granularityMonth:
+		return t.Format("2006-01")
+	case granularityQuarter:
+		return fmt.Sprintf("%d-Q%d", t.Year(), (int(t.Month())-1)/3+1)
+	case granularityYear:
+		return t.Format("2006")
+	default:
+		return t.Format("2006-01-02")
+	}
+}
+
+// count returns how many buckets cover startDate to endDate, without
+// building them.
+func (b bucketer) count(startDate, endDate time.Time) int64 {
+	first, last := b.start(startDate), b.start(endDate)
+	if last.Before(first) {
+		return 0
+	}
+	months := int64(last.Year()-first.Year())*12 + int64(last.Month()-first.Month())
+	switch b.granularity {
+	case granularityMonth:
+		return months + 1
+	case granularityQuarter:
+		return months/3 + 1
+	case granularityYear:
+		return int64(last.Year()-first.Year()) + 1
+	}
+	// rounded to whole days so that daylight saving changes do not count
+	days := (last.Unix() - first.Unix() + 12*60*60) / (24 * 60 * 60)
+	if b.granularity == granularityWeek {
+		return days/7 + 1
+	}
+	return days + 1
+}
+
+// checkBuckets responds with 400 when the period would be split into more
+// than maxBuckets buckets.
+func checkBuckets(c *gin.Context, b bucketer, startDate, endDate time.Time) error {
+	if b.count(startDate, endDate) > maxBuckets {
+		err := fmt.Errorf("the period spans more than %d %s buckets, use a coarser granularity or a shorter period", maxBuckets, b.granularity)
+		c.JSON(400, gin.H{"error": err.Error()})
+		return err
+	}
+	return nil
+}
+
+// setBucketer reads the granularity and week_start query parameters, using
+// the given granularity when none is asked for.
+func setBucketer(c *gin.Context, b *bucketer, granularity string) error {
+	b.granularity = strings.ToLower(c.DefaultQuery("granularity", granularity))
+	switch b.granularity {
+	case granularityDay, granularityWeek, granularityMonth, granularityQuarter, granularityYear:
+	default:
+		err := fmt.Errorf("granularity must be one of day, week, month, quarter or year")
+		c.JSON(400, gin.H{"error": err.Error()})
+		return err
+	}
+	var err error
+	b.weekStart, err = weekStartParam(c)
+	return err
+}
+
+// weekStartParam reads the week_start query parameter, Monday by default.
+func weekStartParam(c *gin.Context) (time.Weekday, error) {
+	weekStart := strings.ToLower(c.DefaultQuery("week_start", "monday"))
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.ToLower(d.String()) == weekStart {
+			return d, nil
+		}
+	}
+	err := fmt.Errorf("week_start must be a day of the week")
+	c.JSON(400, gin.H{"error": err.Error()})
+	return time.Monday, err
+}
+
+type spendingBucket struct {
+	models.Spending
+	Label string
+}
+
+// fillBuckets sums the spendings into the buckets covering startDate to
+// endDate, including empty buckets as zeros so the series is continuous.
+func fillBuckets(spendings []models.Spending, startDate, endDate time.Time, negative bool, b bucketer) []spendingBucket {
+	buckets := []spendingBucket{}
+	index := map[time.Time]int{}
+	for t := b.start(startDate); !t.After(endDate); t = b.next(t) {
+		index[t] = len(buckets)
+		buckets = append(buckets, spendingBucket{Spending: models.Spending{Date: t, Negative: negative}, Label: b.label(t)})
+	}
+	for _, sp := range spendings {
+		date := time.Date(sp.Date.Year(), sp.Date.Month(), sp.Date.Day(), 0, 0, 0, 0, startDate.Location())
+		if i, ok := index[b.start(date)]; ok {
+			buckets[i].Total += sp.Total
+		}
+	}
+	return buckets
+}
+
+type categoryFlow struct {
+	CategoryID uuid.UUID
+	Cname      string
+	Income     int64
+	Expense    int64
+	Net        int64
+}
+
+// categoryFlows lines up expenses and income per category, biggest spenders
+// first.
+func categoryFlows(expenses, income []models.SpendingCategory) []categoryFlow {
+	flows := []categoryFlow{}
+	index := map[uuid.UUID]int{}
+	row := func(sp models.SpendingCategory) *categoryFlow {
+		i, ok := index[sp.CategoryID]
+		if !ok {
+			i = len(flows)
+			index[sp.CategoryID] = i
+			flows = append(flows, categoryFlow{CategoryID: sp.CategoryID, Cname: sp.Cname})
+		}
+		return &flows[i]
+	}
+	for _, sp := range expenses {
+		row(sp).Expense += sp.Total
+	}
+	for _, sp := range income {
+		row(sp).Income += sp.Total
+	}
+	for i := range flows {
+		flows[i].Net = flows[i].Income - flows[i].Expense
+	}
+	return flows
+}
+
+type priorityFlow struct {
+	PriorityID uuid.UUID
+	Pname      string
+	Level      int
+	Income     int64
+	Expense    int64
+	Net        int64
+}
+
+// priorityFlows lines up expenses and income per priority, biggest spenders
+// first.
+func priorityFlows(expenses, income []models.SpendingPriority) []priorityFlow {
+	flows := []priorityFlow{}
+	index := map[uuid.UUID]int{}
+	row := func(sp models.SpendingPriority) *priorityFlow {
+		i, ok := index[sp.PriorityID]
+		if !ok {
+			i = len(flows)
+			index[sp.PriorityID] = i
+			flows = append(flows, priorityFlow{PriorityID: sp.PriorityID, Pname: sp.Pname, Level: sp.Level})
+		}
+		return &flows[i]
+	}
+	for _, sp := range expenses {
+		row(sp).Expense += sp.Total
+	}
+	for _, sp := range income {
+		row(sp).Income += sp.Total
+	}
+	for i := range flows {
+		flows[i].Net = flows[i].Income - flows[i].Expense
+	}
+	return flows
+}
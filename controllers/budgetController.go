@@ -0,0 +1,305 @@
+package controllers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/peternabil/go-api/models"
+)
+
+const (
+	periodWeekly = "weekly"
+	periodCustom = "custom"
+)
+
+// budgetPeriod returns the first and last instant of the budget period that
+// contains date. Weeks start on Monday and anything that is not weekly or
+// custom is treated as monthly.
+func budgetPeriod(budget models.Budget, date time.Time) (time.Time, time.Time) {
+	switch budget.Period {
+	case periodWeekly:
+		day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+		start := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		return start, start.AddDate(0, 0, 7).Add(-time.Nanosecond)
+	case periodCustom:
+		return *budget.StartDate, *budget.EndDate
+	default:
+		start := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+		return start, start.AddDate(0, 1, 0).Add(-time.Nanosecond)
+	}
+}
+
+func (server *Server) bindBudget(c *gin.Context, user models.User, budget *models.Budget) error {
+	var body struct {
+		Name      string     `json:"Name" binding:"required,min=1"`
+		Category  string     `json:"Category"`
+		Priority  string     `json:"Priority"`
+		Amount    int        `json:"Amount" binding:"required,min=1"`
+		Period    string     `json:"Period" binding:"required,oneof=monthly weekly custom"`
+		StartDate *time.Time `json:"StartDate"`
+		EndDate   *time.Time `json:"EndDate"`
+	}
+	err := c.BindJSON(&body)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return err
+	}
+	if (body.Category == "") == (body.Priority == "") {
+		err = errors.New("a budget needs exactly one of category or priority")
+		c.JSON(400, gin.H{"error": err.Error()})
+		return err
+	}
+	if body.Period == periodCustom && (body.StartDate == nil || body.EndDate == nil || !body.EndDate.After(*body.StartDate)) {
+		err = errors.New("custom budgets need a start date before their end date")
+		c.JSON(400, gin.H{"error": err.Error()})
+		return err
+	}
+	categoryID, err := parseOptionalUUID(body.Category)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid category uuid"})
+		return err
+	}
+	if categoryID != nil {
+		cat := models.Category{ID: *categoryID}
+		if _, err = server.store.GetCategory(user.UID, &cat); err != nil {
+			c.JSON(400, gin.H{"error": "category not found"})
+			return err
+		}
+	}
+	priorityID, err := parseOptionalUUID(body.Priority)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid priority uuid"})
+		return err
+	}
+	if priorityID != nil {
+		prio := models.Priority{ID: *priorityID}
+		if _, err = server.store.GetPriority(user.UID, &prio); err != nil {
+			c.JSON(400, gin.H{"error": "priority not found"})
+			return err
+		}
+	}
+	budget.Name = body.Name
+	budget.CategoryID = categoryID
+	budget.PriorityID = priorityID
+	budget.Amount = body.Amount
+	budget.Period = body.Period
+	budget.StartDate = nil
+	budget.EndDate = nil
+	if body.Period == periodCustom {
+		budget.StartDate = body.StartDate
+		budget.EndDate = body.EndDate
+	}
+	budget.UserID = user.UID
+	return nil
+}
+
+func (server *Server) BudgetIndex(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	budgets, err := server.store.GetBudgets(user.UID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no budgets for this user"})
+		return
+	}
+	c.JSON(200, gin.H{
+		"budgets": budgets,
+	})
+}
+
+func (server *Server) BudgetFind(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	bId, uuidErr := uuid.Parse(c.Param("id"))
+	if uuidErr != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "invalid uuid"})
+		return
+	}
+	budget := models.Budget{ID: bId}
+	res, err := server.store.GetBudget(user.UID, &budget)
+	if err != nil {
+		c.Status(404)
+		return
+	}
+	c.JSON(200, gin.H{
+		"budget": res,
+	})
+}
+
+func (server *Server) BudgetCreate(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	budget := models.Budget{}
+	if err := server.bindBudget(c, user, &budget); err != nil {
+		return
+	}
+	res, err := server.store.CreateBudget(&budget)
+	if err != nil {
+		c.Status(400)
+		return
+	}
+	c.JSON(200, gin.H{
+		"budget": res,
+	})
+}
+
+func (server *Server) BudgetEdit(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	bId, uuidErr := uuid.Parse(c.Param("id"))
+	if uuidErr != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "invalid uuid"})
+		return
+	}
+	budget := models.Budget{ID: bId}
+	res, err := server.store.GetBudget(user.UID, &budget)
+	if err != nil {
+		c.Status(404)
+		return
+	}
+	budget = res
+	if err = server.bindBudget(c, user, &budget); err != nil {
+		return
+	}
+	res, err = server.store.EditBudget(&budget)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	c.JSON(200, gin.H{
+		"budget": res,
+	})
+}
+
+func (server *Server) BudgetDelete(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	bId, uuidErr := uuid.Parse(c.Param("id"))
+	if uuidErr != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "invalid uuid"})
+		return
+	}
+	budget := models.Budget{ID: bId, UserID: user.UID}
+	res := server.store.DeleteBudget(&budget)
+	if res != nil {
+		c.Status(400)
+		return
+	}
+	c.JSON(200, gin.H{
+		"budget": budget,
+	})
+}
+
+// BudgetProgress reports how much of each budget has been spent in the period
+// containing the optional date query parameter (today by default).
+func (server *Server) BudgetProgress(c *gin.Context) {
+	loc, err := requestLocation(c)
+	if err != nil {
+		return
+	}
+	date := time.Now().In(loc)
+	if dateStr := c.Query("date"); dateStr != "" {
+		date, err = parseDate(dateStr, loc)
+		if err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	user := server.store.GetUserFromToken(c)
+	budgets, err := server.store.GetBudgets(user.UID)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	categories, err := server.store.GetCategories(user.UID)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	children := map[uuid.UUID][]uuid.UUID{}
+	for _, cat := range categories {
+		if cat.ParentID != nil {
+			children[*cat.ParentID] = append(children[*cat.ParentID], cat.ID)
+		}
+	}
+	type window struct{ start, end time.Time }
+	categoryTotals := map[window]map[uuid.UUID]int64{}
+	priorityTotals := map[window]map[uuid.UUID]int64{}
+	type budgetProgress struct {
+		Budget      models.Budget
+		PeriodStart time.Time
+		PeriodEnd   time.Time
+		Spent       int64
+		Remaining   int64
+		PercentUsed float64
+		DaysLeft    int
+	}
+	progress := []budgetProgress{}
+	for _, budget := range budgets {
+		start, end := budgetPeriod(budget, date)
+		w := window{start, end}
+		var spent int64
+		if budget.CategoryID != nil {
+			totals, ok := categoryTotals[w]
+			if !ok {
+				spendings, err := server.store.GetHighestSpendingCategory(user.UID, start, end, true, 0)
+				if err != nil {
+					c.Status(500)
+					return
+				}
+				totals = map[uuid.UUID]int64{}
+				for _, sp := range spendings {
+					totals[sp.CategoryID] = sp.Total
+				}
+				categoryTotals[w] = totals
+			}
+			// a budget on a parent category covers all of its descendants
+			queue := []uuid.UUID{*budget.CategoryID}
+			seen := map[uuid.UUID]bool{}
+			for len(queue) > 0 {
+				id := queue[0]
+				queue = queue[1:]
+				if seen[id] {
+					continue
+				}
+				seen[id] = true
+				spent += totals[id]
+				queue = append(queue, children[id]...)
+			}
+		} else if budget.PriorityID != nil {
+			totals, ok := priorityTotals[w]
+			if !ok {
+				spendings, err := server.store.GetHighestSpendingPriority(user.UID, start, end, true)
+				if err != nil {
+					c.Status(500)
+					return
+				}
+				totals = map[uuid.UUID]int64{}
+				for _, sp := range spendings {
+					totals[sp.PriorityID] = sp.Total
+				}
+				priorityTotals[w] = totals
+			}
+			spent = totals[*budget.PriorityID]
+		}
+		var percentUsed float64
+		if budget.Amount > 0 {
+			percentUsed = math.Round(float64(spent)/float64(budget.Amount)*10000) / 100
+		}
+		daysLeft := 0
+		if date.Before(end) {
+			daysLeft = int(math.Ceil(end.Sub(date).Hours() / 24))
+		}
+		progress = append(progress, budgetProgress{
+			Budget:      budget,
+			PeriodStart: start,
+			PeriodEnd:   end,
+			Spent:       spent,
+			Remaining:   int64(budget.Amount) - spent,
+			PercentUsed: percentUsed,
+			DaysLeft:    daysLeft,
+		})
+	}
+	c.JSON(200, gin.H{
+		"date":     date,
+		"progress": progress,
+	})
+}
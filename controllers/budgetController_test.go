@@ -0,0 +1,261 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mock_store "github.com/peternabil/go-api/mocks"
+	"github.com/peternabil/go-api/models"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestCreateBudget(t *testing.T) {
+	password := "Password123"
+	encryptedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
+	user := models.User{
+		UID:       uuid.New(),
+		Email:     "user@test.com",
+		FirstName: "test",
+		LastName:  "user",
+		Password:  string(encryptedPass),
+	}
+	category := models.Category{
+		ID:     uuid.New(),
+		Name:   "Category A",
+		UserID: user.UID,
+	}
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mock_store.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "success",
+			body: gin.H{
+				"Name":     "food",
+				"Category": category.ID.String(),
+				"Amount":   500,
+				"Period":   "monthly",
+			},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetCategory(gomock.Any(), gomock.Any()).Times(1).Return(category, nil)
+				store.EXPECT().
+					CreateBudget(gomock.Any()).Times(1).DoAndReturn(func(budget *models.Budget) (models.Budget, error) {
+					require.Equal(t, category.ID, *budget.CategoryID)
+					require.Nil(t, budget.PriorityID)
+					return *budget, nil
+				})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "both category and priority",
+			body: gin.H{
+				"Name":     "food",
+				"Category": category.ID.String(),
+				"Priority": uuid.New().String(),
+				"Amount":   500,
+				"Period":   "monthly",
+			},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "custom without dates",
+			body: gin.H{
+				"Name":     "trip",
+				"Category": category.ID.String(),
+				"Amount":   500,
+				"Period":   "custom",
+			},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "invalid period",
+			body: gin.H{
+				"Name":     "food",
+				"Category": category.ID.String(),
+				"Amount":   500,
+				"Period":   "daily",
+			},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockStore := mock_store.NewMockStore(mockCtrl)
+			tt.buildStubs(mockStore)
+
+			server, _ := NewServer(mockStore, nil)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tt.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest("POST", "/smart-account/api/v1/budget", bytes.NewReader(data))
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(recorder)
+		})
+	}
+}
+
+func TestBudgetProgress(t *testing.T) {
+	password := "Password123"
+	encryptedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
+	user := models.User{
+		UID:       uuid.New(),
+		Email:     "user@test.com",
+		FirstName: "test",
+		LastName:  "user",
+		Password:  string(encryptedPass),
+	}
+	food := uuid.New()
+	restaurants := uuid.New()
+	essential := uuid.New()
+	budgets := []models.Budget{
+		{ID: uuid.New(), Name: "food", CategoryID: &food, Amount: 400, Period: "monthly", UserID: user.UID},
+		{ID: uuid.New(), Name: "essentials", PriorityID: &essential, Amount: 100, Period: "weekly", UserID: user.UID},
+	}
+	categories := []models.Category{
+		{ID: food, Name: "Food", UserID: user.UID},
+		{ID: restaurants, Name: "Restaurants", ParentID: &food, UserID: user.UID},
+	}
+	testCases := []struct {
+		name          string
+		param         string
+		buildStubs    func(store *mock_store.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "success",
+			param: "?date=2023-11-15T12:00:00Z",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetBudgets(user.UID).Times(1).Return(budgets, nil)
+				store.EXPECT().
+					GetCategories(user.UID).Times(1).Return(categories, nil)
+				store.EXPECT().
+					GetHighestSpendingCategory(user.UID, time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC), gomock.Any(), true, 0).Times(1).Return([]models.SpendingCategory{
+					{CategoryID: food, Total: 100},
+					{CategoryID: restaurants, Total: 200},
+				}, nil)
+				store.EXPECT().
+					GetHighestSpendingPriority(user.UID, time.Date(2023, 11, 13, 0, 0, 0, 0, time.UTC), gomock.Any(), true).Times(1).Return([]models.SpendingPriority{
+					{PriorityID: essential, Total: 150},
+				}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					Progress []struct {
+						Spent       int64
+						Remaining   int64
+						PercentUsed float64
+						DaysLeft    int
+					} `json:"progress"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Len(t, res.Progress, 2)
+				require.Equal(t, int64(300), res.Progress[0].Spent)
+				require.Equal(t, int64(100), res.Progress[0].Remaining)
+				require.Equal(t, 75.0, res.Progress[0].PercentUsed)
+				require.Equal(t, 16, res.Progress[0].DaysLeft)
+				require.Equal(t, int64(-50), res.Progress[1].Remaining)
+				require.Equal(t, 150.0, res.Progress[1].PercentUsed)
+				require.Equal(t, 5, res.Progress[1].DaysLeft)
+			},
+		},
+		{
+			name:  "wrong date format",
+			param: "?date=2023-11-15",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "db error",
+			param: "",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetBudgets(user.UID).Times(1).Return(nil, errors.New("db error"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockStore := mock_store.NewMockStore(mockCtrl)
+			tt.buildStubs(mockStore)
+
+			server, _ := NewServer(mockStore, nil)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest("GET", fmt.Sprintf("/smart-account/api/v1/budget/progress%s", tt.param), nil)
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(recorder)
+		})
+	}
+}
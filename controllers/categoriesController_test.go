@@ -90,6 +90,56 @@ func TestListCategories(t *testing.T) {
 	}
 }
 
+func TestListCategoriesTree(t *testing.T) {
+	password := "Password123"
+	encryptedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
+	user := models.User{
+		UID:       uuid.New(),
+		Email:     "user@test.com",
+		FirstName: "test",
+		LastName:  "user",
+		Password:  string(encryptedPass),
+	}
+	food := uuid.New()
+	restaurants := uuid.New()
+	categories := []models.Category{
+		{ID: food, Name: "Food", UserID: user.UID},
+		{ID: restaurants, Name: "Restaurants", ParentID: &food, UserID: user.UID},
+		{ID: uuid.New(), Name: "Fast food", ParentID: &restaurants, UserID: user.UID},
+		{ID: uuid.New(), Name: "Rent", UserID: user.UID},
+	}
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockStore := mock_store.NewMockStore(mockCtrl)
+	mockStore.EXPECT().
+		ReadToken(gomock.Any()).Times(1).Return(user, nil)
+	mockStore.EXPECT().
+		GetUserFromToken(gomock.Any()).Times(1).Return(user)
+	mockStore.EXPECT().
+		GetCategories(gomock.Any()).Times(1).Return(categories, nil)
+
+	server, _ := NewServer(mockStore, nil)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest("GET", "/smart-account/api/v1/category", nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var res struct {
+		Categories []categoryNode `json:"categories"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+	require.Len(t, res.Categories, 2)
+	require.Equal(t, "Food", res.Categories[0].Name)
+	require.Len(t, res.Categories[0].Children, 1)
+	require.Equal(t, "Restaurants", res.Categories[0].Children[0].Name)
+	require.Equal(t, "Fast food", res.Categories[0].Children[0].Children[0].Name)
+	require.Empty(t, res.Categories[1].Children)
+}
+
 func TestGetCategory(t *testing.T) {
 	password := "Password123"
 	encryptedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
@@ -287,6 +337,7 @@ func TestEditCategory(t *testing.T) {
 		Password:  string(encryptedPass),
 	}
 	categoryId := uuid.New()
+	childId := uuid.New()
 	updatedCategory := models.Category{
 		Name:        "Updated Category",
 		Description: "An updated test category",
@@ -359,6 +410,111 @@ func TestEditCategory(t *testing.T) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 		},
+		{
+			name:   "nested under its own child",
+			params: categoryId.String(),
+			body: gin.H{
+				"name":        updatedCategory.Name,
+				"description": updatedCategory.Description,
+				"Parent":      childId.String(),
+			},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetCategory(gomock.Any(), gomock.Any()).Times(1).Return(models.Category{ID: categoryId, Name: "Food"}, nil)
+				store.EXPECT().
+					GetCategories(user.UID).Times(1).Return([]models.Category{
+					{ID: categoryId, Name: "Food"},
+					{ID: childId, Name: "Restaurants", ParentID: &categoryId},
+				}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "nested under another category",
+			params: childId.String(),
+			body: gin.H{
+				"name":        updatedCategory.Name,
+				"description": updatedCategory.Description,
+				"Parent":      categoryId.String(),
+			},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetCategory(gomock.Any(), gomock.Any()).Times(1).Return(models.Category{ID: childId, Name: "Restaurants"}, nil)
+				store.EXPECT().
+					GetCategories(user.UID).Times(1).Return([]models.Category{
+					{ID: categoryId, Name: "Food"},
+					{ID: childId, Name: "Restaurants"},
+				}, nil)
+				store.EXPECT().
+					EditCategory(gomock.Any()).Times(1).DoAndReturn(func(cat *models.Category) (models.Category, error) {
+					require.Equal(t, categoryId, *cat.ParentID)
+					return *cat, nil
+				})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "rename keeps the parent",
+			params: childId.String(),
+			body: gin.H{
+				"name":        updatedCategory.Name,
+				"description": updatedCategory.Description,
+			},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetCategory(gomock.Any(), gomock.Any()).Times(1).Return(models.Category{ID: childId, Name: "Restaurants", ParentID: &categoryId}, nil)
+				store.EXPECT().
+					EditCategory(gomock.Any()).Times(1).DoAndReturn(func(cat *models.Category) (models.Category, error) {
+					require.Equal(t, updatedCategory.Name, cat.Name)
+					require.Equal(t, categoryId, *cat.ParentID)
+					return *cat, nil
+				})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "moved to the top level",
+			params: childId.String(),
+			body: gin.H{
+				"name":        updatedCategory.Name,
+				"description": updatedCategory.Description,
+				"Parent":      "",
+			},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetCategory(gomock.Any(), gomock.Any()).Times(1).Return(models.Category{ID: childId, Name: "Restaurants", ParentID: &categoryId}, nil)
+				store.EXPECT().
+					EditCategory(gomock.Any()).Times(1).DoAndReturn(func(cat *models.Category) (models.Category, error) {
+					require.Nil(t, cat.ParentID)
+					return *cat, nil
+				})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
 		{
 			name:   "invalid input",
 			params: categoryId.String(),
@@ -459,3 +615,147 @@ func TestDeleteCategory(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeCategory(t *testing.T) {
+	password := "Password123"
+	encryptedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
+
+	user := models.User{
+		UID:       uuid.New(),
+		Email:     "user@test.com",
+		FirstName: "test",
+		LastName:  "user",
+		Password:  string(encryptedPass),
+	}
+	sourceId := uuid.New()
+	targetId := uuid.New()
+	findCategory := func(id uuid.UUID, cat *models.Category) (models.Category, error) {
+		return *cat, nil
+	}
+	testCases := []struct {
+		name          string
+		params        string
+		body          gin.H
+		buildStubs    func(store *mock_store.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "success",
+			params: sourceId.String(),
+			body:   gin.H{"Target": targetId.String()},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetCategory(user.UID, gomock.Any()).Times(2).DoAndReturn(findCategory)
+				store.EXPECT().
+					MergeCategories(user.UID, gomock.Any(), gomock.Any()).Times(1).DoAndReturn(func(id uuid.UUID, source, target *models.Category) (models.MergeResult, error) {
+					require.Equal(t, sourceId, source.ID)
+					require.Equal(t, targetId, target.ID)
+					return models.MergeResult{Transactions: 4, Rules: 1}, nil
+				})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					Moved models.MergeResult `json:"moved"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, int64(4), res.Moved.Transactions)
+			},
+		},
+		{
+			name:   "goals follow the merge",
+			params: sourceId.String(),
+			body:   gin.H{"Target": targetId.String()},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetCategory(user.UID, gomock.Any()).Times(2).DoAndReturn(findCategory)
+				store.EXPECT().
+					MergeCategories(user.UID, gomock.Any(), gomock.Any()).Times(1).Return(models.MergeResult{Transactions: 2, Goals: 1}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					Moved models.MergeResult `json:"moved"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, int64(1), res.Moved.Goals)
+			},
+		},
+		{
+			name:   "merge into itself",
+			params: sourceId.String(),
+			body:   gin.H{"Target": sourceId.String()},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "target not found",
+			params: sourceId.String(),
+			body:   gin.H{"Target": targetId.String()},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetCategory(user.UID, gomock.Any()).Times(1).DoAndReturn(findCategory)
+				store.EXPECT().
+					GetCategory(user.UID, gomock.Any()).Times(1).Return(models.Category{}, errors.New("not found"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "db error",
+			params: sourceId.String(),
+			body:   gin.H{"Target": targetId.String()},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetCategory(user.UID, gomock.Any()).Times(2).DoAndReturn(findCategory)
+				store.EXPECT().
+					MergeCategories(user.UID, gomock.Any(), gomock.Any()).Times(1).Return(models.MergeResult{}, errors.New("db error"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockStore := mock_store.NewMockStore(mockCtrl)
+			tt.buildStubs(mockStore)
+
+			server, _ := NewServer(mockStore, nil)
+			recorder := httptest.NewRecorder()
+
+			body, err := json.Marshal(tt.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest("POST", fmt.Sprintf("/smart-account/api/v1/category/%s/merge", tt.params), bytes.NewReader(body))
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(recorder)
+		})
+	}
+}
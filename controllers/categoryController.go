@@ -8,6 +8,64 @@ import (
 	"github.com/peternabil/go-api/models"
 )
 
+type categoryNode struct {
+	models.Category
+	Children []categoryNode
+}
+
+// categoryTree nests the categories under their parents. Categories whose
+// parent is missing are returned as roots.
+func categoryTree(categories []models.Category) []categoryNode {
+	known := map[uuid.UUID]bool{}
+	children := map[uuid.UUID][]models.Category{}
+	for _, cat := range categories {
+		known[cat.ID] = true
+	}
+	roots := []models.Category{}
+	for _, cat := range categories {
+		if cat.ParentID == nil || !known[*cat.ParentID] {
+			roots = append(roots, cat)
+			continue
+		}
+		children[*cat.ParentID] = append(children[*cat.ParentID], cat)
+	}
+	var build func(cats []models.Category) []categoryNode
+	build = func(cats []models.Category) []categoryNode {
+		nodes := []categoryNode{}
+		for _, cat := range cats {
+			nodes = append(nodes, categoryNode{Category: cat, Children: build(children[cat.ID])})
+		}
+		return nodes
+	}
+	return build(roots)
+}
+
+// createsCycle reports whether making parentID the parent of id would make
+// id one of its own ancestors.
+func createsCycle(categories []models.Category, id, parentID uuid.UUID) bool {
+	parents := map[uuid.UUID]*uuid.UUID{}
+	for _, cat := range categories {
+		parents[cat.ID] = cat.ParentID
+	}
+	visited := map[uuid.UUID]bool{}
+	for current := &parentID; current != nil; current = parents[*current] {
+		if *current == id || visited[*current] {
+			return true
+		}
+		visited[*current] = true
+	}
+	return false
+}
+
+func categoryExists(categories []models.Category, id uuid.UUID) bool {
+	for _, cat := range categories {
+		if cat.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
 func (server *Server) CategoryIndex(c *gin.Context) {
 	user := server.store.GetUserFromToken(c)
 	cats, err := server.store.GetCategories(user.UID)
@@ -16,7 +74,7 @@ func (server *Server) CategoryIndex(c *gin.Context) {
 		return
 	}
 	c.JSON(200, gin.H{
-		"categories": cats,
+		"categories": categoryTree(cats),
 	})
 }
 
@@ -38,6 +96,7 @@ func (server *Server) CategoryCreate(c *gin.Context) {
 	var body struct {
 		Name        string `json:"Name" binding:"required,min=1"`
 		Description string `json:"Description" binding:"required,min=1"`
+		Parent      string `json:"Parent"`
 	}
 	user := server.store.GetUserFromToken(c)
 	err := c.BindJSON(&body)
@@ -45,7 +104,19 @@ func (server *Server) CategoryCreate(c *gin.Context) {
 		c.JSON(400, gin.H{"error": err.Error()})
 		return
 	}
-	category := models.Category{Name: body.Name, Description: body.Description, UserID: user.UID}
+	parentID, err := parseOptionalUUID(body.Parent)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid parent uuid"})
+		return
+	}
+	if parentID != nil {
+		parent := models.Category{ID: *parentID}
+		if _, err = server.store.GetCategory(user.UID, &parent); err != nil {
+			c.JSON(400, gin.H{"error": "parent category not found"})
+			return
+		}
+	}
+	category := models.Category{Name: body.Name, Description: body.Description, ParentID: parentID, UserID: user.UID}
 	cat, err := server.store.CreateCategory(&category)
 	if err != nil {
 		c.Status(400)
@@ -58,8 +129,9 @@ func (server *Server) CategoryCreate(c *gin.Context) {
 
 func (server *Server) CategoryEdit(c *gin.Context) {
 	var body struct {
-		Name        string `json:"Name" binding:"min=1"`
-		Description string `json:"Description" binding:"min=1"`
+		Name        string  `json:"Name" binding:"min=1"`
+		Description string  `json:"Description" binding:"min=1"`
+		Parent      *string `json:"Parent"`
 	}
 	catId := c.Param("id")
 	err := c.BindJSON(&body)
@@ -75,6 +147,31 @@ func (server *Server) CategoryEdit(c *gin.Context) {
 		return
 	}
 	cat = res
+	// the parent only changes when the body names one, an empty one moving
+	// the category back to the top level
+	if body.Parent != nil {
+		parentID, err := parseOptionalUUID(*body.Parent)
+		if err != nil {
+			c.JSON(400, gin.H{"error": "invalid parent uuid"})
+			return
+		}
+		if parentID != nil {
+			cats, err := server.store.GetCategories(user.UID)
+			if err != nil {
+				c.Status(500)
+				return
+			}
+			if !categoryExists(cats, *parentID) {
+				c.JSON(400, gin.H{"error": "parent category not found"})
+				return
+			}
+			if createsCycle(cats, cat.ID, *parentID) {
+				c.JSON(400, gin.H{"error": "a category cannot be nested under itself or its descendants"})
+				return
+			}
+		}
+		cat.ParentID = parentID
+	}
 	cat.Name = body.Name
 	cat.Description = body.Description
 	cat, err = server.store.EditCategory(&cat)
@@ -99,3 +196,53 @@ func (server *Server) CategoryDelete(c *gin.Context) {
 		"category": category,
 	})
 }
+
+// CategoryMerge moves every transaction, rule, budget and child category of the
+// category in the path to the target category and deletes it.
+func (server *Server) CategoryMerge(c *gin.Context) {
+	var body struct {
+		Target string `json:"Target" binding:"required"`
+	}
+	err := c.BindJSON(&body)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	sourceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "invalid uuid"})
+		return
+	}
+	targetID, err := uuid.Parse(body.Target)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid target uuid"})
+		return
+	}
+	if sourceID == targetID {
+		c.JSON(400, gin.H{"error": "cannot merge a category into itself"})
+		return
+	}
+	user := server.store.GetUserFromToken(c)
+	source := models.Category{ID: sourceID}
+	source, err = server.store.GetCategory(user.UID, &source)
+	if err != nil {
+		c.Status(404)
+		return
+	}
+	target := models.Category{ID: targetID}
+	target, err = server.store.GetCategory(user.UID, &target)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "target category not found"})
+		return
+	}
+	moved, err := server.store.MergeCategories(user.UID, &source, &target)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "could not merge categories"})
+		return
+	}
+	server.classifiers.Reset(user.UID)
+	c.JSON(200, gin.H{
+		"category": target,
+		"moved":    moved,
+	})
+}
@@ -0,0 +1,199 @@
+package controllers
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	comparePrevious = "previous"
+	compareLastYear = "last_year"
+	compareCustom   = "custom"
+)
+
+type comparisonRow struct {
+	ID           uuid.UUID
+	Name         string
+	Current      int64
+	Previous     int64
+	Delta        int64
+	PercentDelta *float64
+	BiggestMover bool
+}
+
+type comparisonPeriod struct {
+	StartDate time.Time
+	EndDate   time.Time
+	Total     int64
+}
+
+// wholeMonths reports how many calendar months the range covers when it
+// starts on the first of a month and ends on the last day of one.
+func wholeMonths(startDate, endDate time.Time) (int, bool) {
+	if startDate.Day() != 1 || startDate.Hour() != 0 || startDate.Minute() != 0 || startDate.Second() != 0 {
+		return 0, false
+	}
+	if endDate.AddDate(0, 0, 1).Month() == endDate.Month() {
+		return 0, false
+	}
+	months := (endDate.Year()-startDate.Year())*12 + int(endDate.Month()-startDate.Month()) + 1
+	return months, months > 0
+}
+
+// previousPeriod returns the period of the same length right before the
+// given one. Ranges of whole calendar months map onto whole months.
+func previousPeriod(startDate, endDate time.Time) (time.Time, time.Time) {
+	if months, ok := wholeMonths(startDate, endDate); ok {
+		prevStart := startDate.AddDate(0, -months, 0)
+		lastDay := startDate.AddDate(0, 0, -1)
+		prevEnd := time.Date(lastDay.Year(), lastDay.Month(), lastDay.Day(), endDate.Hour(), endDate.Minute(), endDate.Second(), endDate.Nanosecond(), endDate.Location())
+		return prevStart, prevEnd
+	}
+	prevEnd := startDate.Add(-time.Second)
+	return prevEnd.Add(-endDate.Sub(startDate)), prevEnd
+}
+
+// setComparisonDates works out the comparison period from the compare query
+// parameter: the previous period (default), the same period last year, or a
+// custom compare_start_date to compare_end_date.
+func setComparisonDates(c *gin.Context, startDate, endDate time.Time, compareStart, compareEnd *time.Time) error {
+	switch c.DefaultQuery("compare", comparePrevious) {
+	case comparePrevious:
+		*compareStart, *compareEnd = previousPeriod(startDate, endDate)
+	case compareLastYear:
+		*compareStart, *compareEnd = startDate.AddDate(-1, 0, 0), endDate.AddDate(-1, 0, 0)
+	case compareCustom:
+		var err error
+		*compareStart, err = parseDate(c.Query("compare_start_date"), startDate.Location())
+		if err == nil {
+			*compareEnd, err = parseDate(c.Query("compare_end_date"), startDate.Location())
+		}
+		if err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return err
+		}
+	default:
+		err := fmt.Errorf("compare must be one of previous, last_year or custom")
+		c.JSON(400, gin.H{"error": err.Error()})
+		return err
+	}
+	return nil
+}
+
+// compareRows fills in the deltas, orders the rows by the size of their
+// change and flags the first movers rows as the biggest movers.
+func compareRows(rows []comparisonRow, movers int) []comparisonRow {
+	for i := range rows {
+		rows[i].Delta = rows[i].Current - rows[i].Previous
+		if rows[i].Previous != 0 {
+			percent := math.Round(float64(rows[i].Delta)/float64(rows[i].Previous)*10000) / 100
+			rows[i].PercentDelta = &percent
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i].Delta, rows[j].Delta
+		if a < 0 {
+			a = -a
+		}
+		if b < 0 {
+			b = -b
+		}
+		return a > b
+	})
+	for i := 0; i < len(rows) && i < movers; i++ {
+		rows[i].BiggestMover = rows[i].Delta != 0
+	}
+	return rows
+}
+
+// GetComparison compares category and priority totals between the given
+// period and a comparison period.
+func (server *Server) GetComparison(c *gin.Context) {
+	var startDate, endDate, compareStart, compareEnd time.Time
+	var negative bool
+	err := setDates(c, &startDate, &endDate)
+	if err != nil {
+		return
+	}
+	err = setNegative(c, &negative)
+	if err != nil {
+		return
+	}
+	var depth int
+	err = setDepth(c, &depth)
+	if err != nil {
+		return
+	}
+	err = setComparisonDates(c, startDate, endDate, &compareStart, &compareEnd)
+	if err != nil {
+		return
+	}
+	movers, err := strconv.Atoi(c.DefaultQuery("movers", "3"))
+	if err != nil || movers < 0 {
+		c.JSON(400, gin.H{"error": "movers must be a non-negative number"})
+		return
+	}
+	user := server.store.GetUserFromToken(c)
+	current := comparisonPeriod{StartDate: startDate, EndDate: endDate}
+	previous := comparisonPeriod{StartDate: compareStart, EndDate: compareEnd}
+
+	categories := []comparisonRow{}
+	categoryIndex := map[uuid.UUID]int{}
+	for _, period := range []*comparisonPeriod{&current, &previous} {
+		spendings, err := server.store.GetHighestSpendingCategory(user.UID, period.StartDate, period.EndDate, negative, depth)
+		if err != nil {
+			c.Status(500)
+			return
+		}
+		for _, sp := range spendings {
+			i, ok := categoryIndex[sp.CategoryID]
+			if !ok {
+				i = len(categories)
+				categoryIndex[sp.CategoryID] = i
+				categories = append(categories, comparisonRow{ID: sp.CategoryID, Name: sp.Cname})
+			}
+			if period == &current {
+				categories[i].Current += sp.Total
+			} else {
+				categories[i].Previous += sp.Total
+			}
+			period.Total += sp.Total
+		}
+	}
+
+	priorities := []comparisonRow{}
+	priorityIndex := map[uuid.UUID]int{}
+	for _, period := range []*comparisonPeriod{&current, &previous} {
+		spendings, err := server.store.GetHighestSpendingPriority(user.UID, period.StartDate, period.EndDate, negative)
+		if err != nil {
+			c.Status(500)
+			return
+		}
+		for _, sp := range spendings {
+			i, ok := priorityIndex[sp.PriorityID]
+			if !ok {
+				i = len(priorities)
+				priorityIndex[sp.PriorityID] = i
+				priorities = append(priorities, comparisonRow{ID: sp.PriorityID, Name: sp.Pname})
+			}
+			if period == &current {
+				priorities[i].Current += sp.Total
+			} else {
+				priorities[i].Previous += sp.Total
+			}
+		}
+	}
+
+	c.JSON(200, gin.H{
+		"current":    current,
+		"previous":   previous,
+		"categories": compareRows(categories, movers),
+		"priorities": compareRows(priorities, movers),
+	})
+}
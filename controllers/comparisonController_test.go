@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mock_store "github.com/peternabil/go-api/mocks"
+	"github.com/peternabil/go-api/models"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestGetComparison(t *testing.T) {
+	password := "Password123"
+	encryptedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
+	user := models.User{
+		UID:       uuid.New(),
+		Email:     "user@test.com",
+		FirstName: "test",
+		LastName:  "user",
+		Password:  string(encryptedPass),
+	}
+	restaurants := uuid.New()
+	groceries := uuid.New()
+	rent := uuid.New()
+	essential := uuid.New()
+	november := time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)
+	october := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name          string
+		param         string
+		buildStubs    func(store *mock_store.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "previous month",
+			param: "?start_date=2023-11-01T00:00:00Z&end_date=2023-11-30T23:59:59Z&negative=true&movers=1",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetHighestSpendingCategory(user.UID, november, time.Date(2023, 11, 30, 23, 59, 59, 0, time.UTC), true, 0).Times(1).Return([]models.SpendingCategory{
+					{CategoryID: rent, Cname: "Rent", Total: 1000},
+					{CategoryID: restaurants, Cname: "Restaurants", Total: 300},
+				}, nil)
+				store.EXPECT().
+					GetHighestSpendingCategory(user.UID, october, time.Date(2023, 10, 31, 23, 59, 59, 0, time.UTC), true, 0).Times(1).Return([]models.SpendingCategory{
+					{CategoryID: rent, Cname: "Rent", Total: 1000},
+					{CategoryID: restaurants, Cname: "Restaurants", Total: 200},
+					{CategoryID: groceries, Cname: "Groceries", Total: 50},
+				}, nil)
+				store.EXPECT().
+					GetHighestSpendingPriority(user.UID, november, gomock.Any(), true).Times(1).Return([]models.SpendingPriority{
+					{PriorityID: essential, Pname: "Essential", Total: 1300},
+				}, nil)
+				store.EXPECT().
+					GetHighestSpendingPriority(user.UID, october, gomock.Any(), true).Times(1).Return([]models.SpendingPriority{
+					{PriorityID: essential, Pname: "Essential", Total: 1250},
+				}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					Current    comparisonPeriod `json:"current"`
+					Previous   comparisonPeriod `json:"previous"`
+					Categories []comparisonRow  `json:"categories"`
+					Priorities []comparisonRow  `json:"priorities"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, int64(1300), res.Current.Total)
+				require.Equal(t, int64(1250), res.Previous.Total)
+				require.Len(t, res.Categories, 3)
+				require.Equal(t, restaurants, res.Categories[0].ID)
+				require.Equal(t, int64(100), res.Categories[0].Delta)
+				require.Equal(t, 50.0, *res.Categories[0].PercentDelta)
+				require.True(t, res.Categories[0].BiggestMover)
+				require.Equal(t, groceries, res.Categories[1].ID)
+				require.Equal(t, -100.0, *res.Categories[1].PercentDelta)
+				require.False(t, res.Categories[1].BiggestMover)
+				require.Equal(t, int64(0), res.Categories[2].Delta)
+				require.Equal(t, int64(50), res.Priorities[0].Delta)
+			},
+		},
+		{
+			name:  "same period last year",
+			param: "?start_date=2023-11-10T00:00:00Z&end_date=2023-11-20T00:00:00Z&negative=true&compare=last_year",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetHighestSpendingCategory(user.UID, gomock.Any(), gomock.Any(), true, 0).Times(1).Return([]models.SpendingCategory{}, nil)
+				store.EXPECT().
+					GetHighestSpendingCategory(user.UID, time.Date(2022, 11, 10, 0, 0, 0, 0, time.UTC), time.Date(2022, 11, 20, 0, 0, 0, 0, time.UTC), true, 0).Times(1).Return([]models.SpendingCategory{}, nil)
+				store.EXPECT().
+					GetHighestSpendingPriority(user.UID, gomock.Any(), gomock.Any(), true).Times(2).Return([]models.SpendingPriority{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "custom without dates",
+			param: "?start_date=2023-11-10T00:00:00Z&end_date=2023-11-20T00:00:00Z&negative=true&compare=custom",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "unknown comparison",
+			param: "?start_date=2023-11-10T00:00:00Z&end_date=2023-11-20T00:00:00Z&negative=true&compare=yesterday",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockStore := mock_store.NewMockStore(mockCtrl)
+			tt.buildStubs(mockStore)
+
+			server, _ := NewServer(mockStore, nil)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest("GET", fmt.Sprintf("/smart-account/api/v1/compare%s", tt.param), nil)
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(recorder)
+		})
+	}
+}
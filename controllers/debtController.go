@@ -0,0 +1,215 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/peternabil/go-api/models"
+	"github.com/peternabil/go-api/planner"
+)
+
+type debtStatus struct {
+	Debt    models.Debt
+	Paid    int64
+	Balance int64
+}
+
+// checkDebt parses an optional debt id and makes sure it belongs to the user.
+func (server *Server) checkDebt(c *gin.Context, user models.User, id string) (*uuid.UUID, error) {
+	debtId, err := parseOptionalUUID(id)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid debt uuid"})
+		return nil, err
+	}
+	if debtId != nil {
+		debt := models.Debt{ID: *debtId}
+		if _, err = server.store.GetDebt(user.UID, &debt); err != nil {
+			c.JSON(400, gin.H{"error": "debt not found"})
+			return nil, err
+		}
+	}
+	return debtId, nil
+}
+
+func (server *Server) bindDebt(c *gin.Context, user models.User, debt *models.Debt) error {
+	var body struct {
+		Name           string  `json:"Name" binding:"required,min=1"`
+		Principal      int     `json:"Principal" binding:"required,min=1"`
+		APR            float64 `json:"APR" binding:"min=0,max=100"`
+		MinimumPayment int     `json:"MinimumPayment" binding:"min=0"`
+		DueDay         *int    `json:"DueDay" binding:"omitempty,min=1,max=31"`
+	}
+	err := c.BindJSON(&body)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return err
+	}
+	debt.Name = body.Name
+	debt.Principal = body.Principal
+	debt.APR = body.APR
+	debt.MinimumPayment = body.MinimumPayment
+	debt.DueDay = body.DueDay
+	debt.UserID = user.UID
+	return nil
+}
+
+// debtStatuses pairs each debt with what has been paid towards it so far.
+func (server *Server) debtStatuses(user models.User) ([]debtStatus, error) {
+	debts, err := server.store.GetDebts(user.UID)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := server.store.GetDebtPayments(user.UID)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]debtStatus, 0, len(debts))
+	for _, debt := range debts {
+		balance := int64(debt.Principal) - payments[debt.ID]
+		if balance < 0 {
+			balance = 0
+		}
+		statuses = append(statuses, debtStatus{Debt: debt, Paid: payments[debt.ID], Balance: balance})
+	}
+	return statuses, nil
+}
+
+func (server *Server) DebtIndex(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	debts, err := server.debtStatuses(user)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no debts for this user"})
+		return
+	}
+	c.JSON(200, gin.H{
+		"debts": debts,
+	})
+}
+
+func (server *Server) DebtFind(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	dId, uuidErr := uuid.Parse(c.Param("id"))
+	if uuidErr != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "invalid uuid"})
+		return
+	}
+	debt := models.Debt{ID: dId}
+	res, err := server.store.GetDebt(user.UID, &debt)
+	if err != nil {
+		c.Status(404)
+		return
+	}
+	c.JSON(200, gin.H{
+		"debt": res,
+	})
+}
+
+func (server *Server) DebtCreate(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	debt := models.Debt{}
+	if err := server.bindDebt(c, user, &debt); err != nil {
+		return
+	}
+	res, err := server.store.CreateDebt(&debt)
+	if err != nil {
+		c.Status(400)
+		return
+	}
+	c.JSON(200, gin.H{
+		"debt": res,
+	})
+}
+
+func (server *Server) DebtEdit(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	dId, uuidErr := uuid.Parse(c.Param("id"))
+	if uuidErr != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "invalid uuid"})
+		return
+	}
+	debt := models.Debt{ID: dId}
+	res, err := server.store.GetDebt(user.UID, &debt)
+	if err != nil {
+		c.Status(404)
+		return
+	}
+	debt = res
+	if err = server.bindDebt(c, user, &debt); err != nil {
+		return
+	}
+	res, err = server.store.EditDebt(&debt)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	c.JSON(200, gin.H{
+		"debt": res,
+	})
+}
+
+func (server *Server) DebtDelete(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	dId, uuidErr := uuid.Parse(c.Param("id"))
+	if uuidErr != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "invalid uuid"})
+		return
+	}
+	debt := models.Debt{ID: dId, UserID: user.UID}
+	res := server.store.DeleteDebt(&debt)
+	if res != nil {
+		c.Status(400)
+		return
+	}
+	c.JSON(200, gin.H{
+		"debt": debt,
+	})
+}
+
+// DebtPlan simulates paying the outstanding debts off with the monthly budget
+// query parameter using both the snowball and the avalanche strategy.
+func (server *Server) DebtPlan(c *gin.Context) {
+	budget, err := strconv.ParseInt(c.Query("budget"), 10, 64)
+	if err != nil || budget <= 0 {
+		c.JSON(400, gin.H{"error": "budget must be a positive number"})
+		return
+	}
+	loc, err := requestLocation(c)
+	if err != nil {
+		return
+	}
+	user := server.store.GetUserFromToken(c)
+	statuses, err := server.debtStatuses(user)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	debts := make([]planner.Debt, 0, len(statuses))
+	for _, s := range statuses {
+		debts = append(debts, planner.Debt{
+			ID:             s.Debt.ID,
+			Name:           s.Debt.Name,
+			Balance:        s.Balance,
+			APR:            s.Debt.APR,
+			MinimumPayment: int64(s.Debt.MinimumPayment),
+			DueDay:         s.Debt.DueDay,
+		})
+	}
+	now := time.Now().In(loc)
+	plans := gin.H{}
+	for _, strategy := range []string{planner.Snowball, planner.Avalanche} {
+		plan, err := planner.Simulate(debts, budget, strategy, now)
+		if err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		plans[strategy] = plan
+	}
+	c.JSON(200, gin.H{
+		"budget": budget,
+		"debts":  statuses,
+		"plans":  plans,
+	})
+}
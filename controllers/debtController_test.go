@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mock_store "github.com/peternabil/go-api/mocks"
+	"github.com/peternabil/go-api/models"
+	"github.com/peternabil/go-api/planner"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestDebtPlan(t *testing.T) {
+	password := "Password123"
+	encryptedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
+	user := models.User{
+		UID:       uuid.New(),
+		Email:     "user@test.com",
+		FirstName: "test",
+		LastName:  "user",
+		Password:  string(encryptedPass),
+	}
+	dueDay := 15
+	card := models.Debt{ID: uuid.New(), Name: "card", Principal: 3000, APR: 24, MinimumPayment: 100, DueDay: &dueDay, UserID: user.UID}
+	loan := models.Debt{ID: uuid.New(), Name: "loan", Principal: 1000, APR: 6, MinimumPayment: 50, UserID: user.UID}
+	debts := []models.Debt{card, loan}
+	payments := map[uuid.UUID]int64{card.ID: 500}
+	testCases := []struct {
+		name          string
+		param         string
+		buildStubs    func(store *mock_store.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "success",
+			param: "?budget=500",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetDebts(user.UID).Times(1).Return(debts, nil)
+				store.EXPECT().
+					GetDebtPayments(user.UID).Times(1).Return(payments, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					Debts []debtStatus            `json:"debts"`
+					Plans map[string]planner.Plan `json:"plans"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, int64(2500), res.Debts[0].Balance)
+				snowball := res.Plans[planner.Snowball]
+				avalanche := res.Plans[planner.Avalanche]
+				// the snowball clears the small loan first, the avalanche the card
+				require.Equal(t, loan.ID, snowball.Payoffs[0].DebtID)
+				require.Equal(t, card.ID, avalanche.Payoffs[0].DebtID)
+				require.Less(t, avalanche.TotalInterest, snowball.TotalInterest)
+				require.Len(t, snowball.Schedule, snowball.Months)
+				require.Equal(t, 15, avalanche.Schedule[0].Payments[0].Date.Day())
+				for _, plan := range []planner.Plan{snowball, avalanche} {
+					require.Equal(t, int64(3500)+plan.TotalInterest, plan.TotalPaid)
+				}
+			},
+		},
+		{
+			name:  "budget below minimums",
+			param: "?budget=120",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetDebts(user.UID).Times(1).Return(debts, nil)
+				store.EXPECT().
+					GetDebtPayments(user.UID).Times(1).Return(payments, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "missing budget",
+			param: "",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockStore := mock_store.NewMockStore(mockCtrl)
+			tt.buildStubs(mockStore)
+
+			server, _ := NewServer(mockStore, nil)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest("GET", fmt.Sprintf("/smart-account/api/v1/debt/plan%s", tt.param), nil)
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(recorder)
+		})
+	}
+}
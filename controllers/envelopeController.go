@@ -0,0 +1,265 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/peternabil/go-api/models"
+)
+
+const (
+	envelopeAssign = "assign"
+	envelopeMove   = "move"
+	envelopeMerge  = "merge"
+	monthLayout    = "2006-01"
+)
+
+// parseMonth reads a "2006-01" month, defaulting to the current one in the
+// request's time zone. Months are kept as UTC labels; the store is asked for
+// them with inLocation.
+func parseMonth(c *gin.Context, value string) (time.Time, error) {
+	if value == "" {
+		loc, err := userLocation(c)
+		if err != nil {
+			return time.Time{}, err
+		}
+		now := time.Now().In(loc)
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC), nil
+	}
+	return time.Parse(monthLayout, value)
+}
+
+// checkCategory responds with 400 unless id is one of the user's categories.
+func (server *Server) checkCategory(c *gin.Context, user models.User, id string) (uuid.UUID, error) {
+	catId, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid category uuid"})
+		return uuid.Nil, err
+	}
+	cat := models.Category{ID: catId}
+	if _, err = server.store.GetCategory(user.UID, &cat); err != nil {
+		c.JSON(400, gin.H{"error": "category not found"})
+		return uuid.Nil, err
+	}
+	return catId, nil
+}
+
+// EnvelopeIndex reports every category envelope for the month query parameter
+// together with the income that is still ready to assign. Envelope history
+// starts at the earliest month anything was assigned; from then on leftover
+// or overspent money rolls over into the following month.
+func (server *Server) EnvelopeIndex(c *gin.Context) {
+	month, err := parseMonth(c, c.Query("month"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	user := server.store.GetUserFromToken(c)
+	events, err := server.store.GetEnvelopeEvents(user.UID, time.Time{}, month)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	start := month
+	for _, event := range events {
+		if event.Month.Before(start) {
+			start = event.Month
+		}
+	}
+	end := month.AddDate(0, 1, 0).Add(-time.Nanosecond)
+	loc, err := userLocation(c)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	expenses, err := server.store.GetMonthlySpendingCategory(user.UID, inLocation(start, loc), inLocation(end, loc), true)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	income, err := server.store.GetMonthlySpendingCategory(user.UID, inLocation(start, loc), inLocation(end, loc), false)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	categories, err := server.store.GetCategories(user.UID)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	type envelope struct {
+		Category  models.Category
+		Rollover  int64
+		Assigned  int64
+		Activity  int64
+		Available int64
+	}
+	envelopes := make([]envelope, len(categories))
+	index := map[uuid.UUID]*envelope{}
+	for i, cat := range categories {
+		envelopes[i].Category = cat
+		index[cat.ID] = &envelopes[i]
+	}
+	// credit adds amount to the envelope, either as this month's assignment
+	// or as part of what rolled over from earlier months
+	credit := func(id uuid.UUID, when time.Time, amount int64) {
+		env, ok := index[id]
+		if !ok {
+			return
+		}
+		if when.Before(month) {
+			env.Rollover += amount
+		} else {
+			env.Assigned += amount
+		}
+	}
+	var assigned int64
+	for _, event := range events {
+		credit(event.CategoryID, event.Month, int64(event.Amount))
+		if (event.Kind == envelopeMove || event.Kind == envelopeMerge) && event.FromCategoryID != nil {
+			credit(*event.FromCategoryID, event.Month, -int64(event.Amount))
+		} else {
+			assigned += int64(event.Amount)
+		}
+	}
+	for _, sp := range expenses {
+		env, ok := index[sp.CategoryID]
+		if !ok {
+			continue
+		}
+		if sp.Date.Before(month) {
+			env.Rollover -= sp.Total
+		} else {
+			env.Activity += sp.Total
+		}
+	}
+	var earned int64
+	for _, sp := range income {
+		earned += sp.Total
+	}
+	for i := range envelopes {
+		envelopes[i].Available = envelopes[i].Rollover + envelopes[i].Assigned - envelopes[i].Activity
+	}
+	c.JSON(200, gin.H{
+		"month":           month.Format(monthLayout),
+		"ready_to_assign": earned - assigned,
+		"envelopes":       envelopes,
+	})
+}
+
+// EnvelopeEvents lists the allocation history, optionally for a single month.
+func (server *Server) EnvelopeEvents(c *gin.Context) {
+	start := time.Time{}
+	end := time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+	if monthStr := c.Query("month"); monthStr != "" {
+		month, err := parseMonth(c, monthStr)
+		if err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+		start, end = month, month
+	}
+	user := server.store.GetUserFromToken(c)
+	events, err := server.store.GetEnvelopeEvents(user.UID, start, end)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	c.JSON(200, gin.H{
+		"events": events,
+	})
+}
+
+// EnvelopeAssign adds money to (or, with a negative amount, takes it back
+// from) a category envelope.
+func (server *Server) EnvelopeAssign(c *gin.Context) {
+	var body struct {
+		Category string `json:"Category" binding:"required"`
+		Month    string `json:"Month"`
+		Amount   int    `json:"Amount" binding:"required"`
+		Note     string `json:"Note"`
+	}
+	err := c.BindJSON(&body)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	month, err := parseMonth(c, body.Month)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	user := server.store.GetUserFromToken(c)
+	catId, err := server.checkCategory(c, user, body.Category)
+	if err != nil {
+		return
+	}
+	event := models.EnvelopeEvent{
+		Kind:       envelopeAssign,
+		Month:      month,
+		CategoryID: catId,
+		Amount:     body.Amount,
+		Note:       body.Note,
+		UserID:     user.UID,
+	}
+	res, err := server.store.CreateEnvelopeEvent(&event)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	c.JSON(200, gin.H{
+		"event": res,
+	})
+}
+
+// EnvelopeMove moves money from one category envelope to another.
+func (server *Server) EnvelopeMove(c *gin.Context) {
+	var body struct {
+		From   string `json:"From" binding:"required"`
+		To     string `json:"To" binding:"required"`
+		Month  string `json:"Month"`
+		Amount int    `json:"Amount" binding:"required,min=1"`
+		Note   string `json:"Note"`
+	}
+	err := c.BindJSON(&body)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	month, err := parseMonth(c, body.Month)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	if body.From == body.To {
+		c.JSON(400, gin.H{"error": "cannot move money into the same envelope"})
+		return
+	}
+	user := server.store.GetUserFromToken(c)
+	fromId, err := server.checkCategory(c, user, body.From)
+	if err != nil {
+		return
+	}
+	toId, err := server.checkCategory(c, user, body.To)
+	if err != nil {
+		return
+	}
+	event := models.EnvelopeEvent{
+		Kind:           envelopeMove,
+		Month:          month,
+		CategoryID:     toId,
+		FromCategoryID: &fromId,
+		Amount:         body.Amount,
+		Note:           body.Note,
+		UserID:         user.UID,
+	}
+	res, err := server.store.CreateEnvelopeEvent(&event)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	c.JSON(200, gin.H{
+		"event": res,
+	})
+}
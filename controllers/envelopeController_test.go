@@ -0,0 +1,301 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mock_store "github.com/peternabil/go-api/mocks"
+	"github.com/peternabil/go-api/models"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestEnvelopeIndex(t *testing.T) {
+	password := "Password123"
+	encryptedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
+	user := models.User{
+		UID:       uuid.New(),
+		Email:     "user@test.com",
+		FirstName: "test",
+		LastName:  "user",
+		Password:  string(encryptedPass),
+	}
+	food := models.Category{ID: uuid.New(), Name: "Food", UserID: user.UID}
+	rent := models.Category{ID: uuid.New(), Name: "Rent", UserID: user.UID}
+	october := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	november := time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)
+	events := []models.EnvelopeEvent{
+		{Kind: "assign", Month: october, CategoryID: food.ID, Amount: 300},
+		{Kind: "assign", Month: october, CategoryID: rent.ID, Amount: 1000},
+		{Kind: "assign", Month: november, CategoryID: food.ID, Amount: 200},
+		{Kind: "move", Month: november, CategoryID: food.ID, FromCategoryID: &rent.ID, Amount: 50},
+	}
+	testCases := []struct {
+		name          string
+		param         string
+		buildStubs    func(store *mock_store.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "success",
+			param: "?month=2023-11",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetEnvelopeEvents(user.UID, time.Time{}, november).Times(1).Return(events, nil)
+				store.EXPECT().
+					GetMonthlySpendingCategory(user.UID, october, gomock.Any(), true).Times(1).Return([]models.SpendingCategory{
+					{Date: october, CategoryID: food.ID, Total: 350},
+					{Date: october, CategoryID: rent.ID, Total: 900},
+					{Date: november, CategoryID: food.ID, Total: 100},
+				}, nil)
+				store.EXPECT().
+					GetMonthlySpendingCategory(user.UID, october, gomock.Any(), false).Times(1).Return([]models.SpendingCategory{
+					{Date: october, Total: 1500},
+					{Date: november, Total: 400},
+				}, nil)
+				store.EXPECT().
+					GetCategories(user.UID).Times(1).Return([]models.Category{food, rent}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					ReadyToAssign int64 `json:"ready_to_assign"`
+					Envelopes     []struct {
+						Rollover  int64
+						Assigned  int64
+						Activity  int64
+						Available int64
+					} `json:"envelopes"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, int64(400), res.ReadyToAssign)
+				require.Len(t, res.Envelopes, 2)
+				// food overspent by 50 in october, so that carries over
+				require.Equal(t, int64(-50), res.Envelopes[0].Rollover)
+				require.Equal(t, int64(250), res.Envelopes[0].Assigned)
+				require.Equal(t, int64(100), res.Envelopes[0].Activity)
+				require.Equal(t, int64(100), res.Envelopes[0].Available)
+				require.Equal(t, int64(100), res.Envelopes[1].Rollover)
+				require.Equal(t, int64(-50), res.Envelopes[1].Assigned)
+				require.Equal(t, int64(50), res.Envelopes[1].Available)
+			},
+		},
+		{
+			name:  "merged category",
+			param: "?month=2023-11",
+			buildStubs: func(store *mock_store.MockStore) {
+				snacks := uuid.New()
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetEnvelopeEvents(user.UID, time.Time{}, november).Times(1).Return([]models.EnvelopeEvent{
+					{Kind: "assign", Month: november, CategoryID: snacks, Amount: 100},
+					{Kind: "merge", Month: november, CategoryID: food.ID, FromCategoryID: &snacks, Amount: 100},
+				}, nil)
+				store.EXPECT().
+					GetMonthlySpendingCategory(user.UID, november, gomock.Any(), true).Times(1).Return([]models.SpendingCategory{
+					{Date: november, CategoryID: food.ID, Total: 60},
+				}, nil)
+				store.EXPECT().
+					GetMonthlySpendingCategory(user.UID, november, gomock.Any(), false).Times(1).Return([]models.SpendingCategory{
+					{Date: november, Total: 500},
+				}, nil)
+				store.EXPECT().
+					GetCategories(user.UID).Times(1).Return([]models.Category{food}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					ReadyToAssign int64 `json:"ready_to_assign"`
+					Envelopes     []struct {
+						Assigned  int64
+						Available int64
+					} `json:"envelopes"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				// the merge hands money over without assigning any more
+				require.Equal(t, int64(400), res.ReadyToAssign)
+				require.Len(t, res.Envelopes, 1)
+				require.Equal(t, int64(100), res.Envelopes[0].Assigned)
+				require.Equal(t, int64(40), res.Envelopes[0].Available)
+			},
+		},
+		{
+			name:  "wrong month format",
+			param: "?month=11-2023",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "db error",
+			param: "?month=2023-11",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetEnvelopeEvents(user.UID, gomock.Any(), gomock.Any()).Times(1).Return(nil, errors.New("db error"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockStore := mock_store.NewMockStore(mockCtrl)
+			tt.buildStubs(mockStore)
+
+			server, _ := NewServer(mockStore, nil)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest("GET", fmt.Sprintf("/smart-account/api/v1/envelope%s", tt.param), nil)
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(recorder)
+		})
+	}
+}
+
+func TestEnvelopeMove(t *testing.T) {
+	password := "Password123"
+	encryptedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
+	user := models.User{
+		UID:       uuid.New(),
+		Email:     "user@test.com",
+		FirstName: "test",
+		LastName:  "user",
+		Password:  string(encryptedPass),
+	}
+	food := models.Category{ID: uuid.New(), Name: "Food", UserID: user.UID}
+	rent := models.Category{ID: uuid.New(), Name: "Rent", UserID: user.UID}
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mock_store.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "success",
+			body: gin.H{
+				"From":   rent.ID.String(),
+				"To":     food.ID.String(),
+				"Month":  "2023-11",
+				"Amount": 50,
+				"Note":   "dinner party",
+			},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetCategory(user.UID, gomock.Any()).Times(2).Return(food, nil)
+				store.EXPECT().
+					CreateEnvelopeEvent(gomock.Any()).Times(1).DoAndReturn(func(event *models.EnvelopeEvent) (models.EnvelopeEvent, error) {
+					require.Equal(t, "move", event.Kind)
+					require.Equal(t, food.ID, event.CategoryID)
+					require.Equal(t, rent.ID, *event.FromCategoryID)
+					require.Equal(t, time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC), event.Month)
+					return *event, nil
+				})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "same envelope",
+			body: gin.H{
+				"From":   food.ID.String(),
+				"To":     food.ID.String(),
+				"Amount": 50,
+			},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "category not found",
+			body: gin.H{
+				"From":   rent.ID.String(),
+				"To":     food.ID.String(),
+				"Amount": 50,
+			},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetCategory(user.UID, gomock.Any()).Times(1).Return(models.Category{}, errors.New("not found"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "non positive amount",
+			body: gin.H{
+				"From":   rent.ID.String(),
+				"To":     food.ID.String(),
+				"Amount": -50,
+			},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockStore := mock_store.NewMockStore(mockCtrl)
+			tt.buildStubs(mockStore)
+
+			server, _ := NewServer(mockStore, nil)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tt.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest("POST", "/smart-account/api/v1/envelope/move", bytes.NewReader(data))
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(recorder)
+		})
+	}
+}
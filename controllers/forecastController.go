@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/peternabil/go-api/forecast"
+	"github.com/peternabil/go-api/models"
+)
+
+const (
+	// maxForecastLookback caps how many days of history a forecast learns from.
+	maxForecastLookback = 730
+	// maxForecastPeriod caps how many days the forecast period can span.
+	maxForecastPeriod = 366
+)
+
+// GetForecast projects where spending will land at the end of the period.
+// The period defaults to the calendar month containing date (now by
+// default); range or start_date and end_date pick a custom one. The
+// projection learns from the lookback days (90 by default) before the period
+// starts and expects the recurring charges the user confirmed.
+func (server *Server) GetForecast(c *gin.Context) {
+	loc, err := requestLocation(c)
+	if err != nil {
+		return
+	}
+	asOf := time.Now().In(loc)
+	if dateStr := c.Query("date"); dateStr != "" {
+		asOf, err = parseDate(dateStr, loc)
+		if err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	startDate := time.Date(asOf.Year(), asOf.Month(), 1, 0, 0, 0, 0, asOf.Location())
+	endDate := startDate.AddDate(0, 1, 0).Add(-time.Second)
+	if c.Query("range") != "" || c.Query("start_date") != "" || c.Query("end_date") != "" {
+		if err := setDates(c, &startDate, &endDate); err != nil {
+			return
+		}
+	}
+	if endDate.Sub(startDate) > maxForecastPeriod*24*time.Hour {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("the period can span at most %d days", maxForecastPeriod)})
+		return
+	}
+	if asOf.Before(startDate) || asOf.After(endDate) {
+		c.JSON(400, gin.H{"error": "date must fall inside the period"})
+		return
+	}
+	negative, err := strconv.ParseBool(c.DefaultQuery("negative", "true"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	lookback, err := strconv.Atoi(c.DefaultQuery("lookback", "90"))
+	if err != nil || lookback <= 0 || lookback > maxForecastLookback {
+		c.JSON(400, gin.H{"error": fmt.Sprintf("lookback must be between 1 and %d days", maxForecastLookback)})
+		return
+	}
+	user := server.store.GetUserFromToken(c)
+	historyStart := startDate.AddDate(0, 0, -lookback)
+	transactions, err := server.store.GetTransactionsDateRange(user.UID, historyStart, asOf)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	recurrings, err := server.store.GetRecurrings(user.UID)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	in := forecast.Input{
+		History:      []models.Transaction{},
+		Current:      []models.Transaction{},
+		Recurring:    []models.Recurring{},
+		HistoryStart: historyStart,
+		Start:        startDate,
+		AsOf:         asOf,
+		End:          endDate,
+	}
+	// recurring charges are only ever detected among expenses
+	for _, r := range recurrings {
+		if negative && r.Status == recurringConfirmed {
+			in.Recurring = append(in.Recurring, r)
+		}
+	}
+	for _, t := range transactions {
+		if t.Negative != negative {
+			continue
+		}
+		if t.CreatedAt.Before(startDate) {
+			in.History = append(in.History, t)
+		} else {
+			in.Current = append(in.Current, t)
+		}
+	}
+	c.JSON(200, gin.H{
+		"start_date": startDate,
+		"end_date":   endDate,
+		"date":       asOf,
+		"forecast":   forecast.Forecast(in),
+	})
+}
@@ -0,0 +1,218 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/peternabil/go-api/forecast"
+	mock_store "github.com/peternabil/go-api/mocks"
+	"github.com/peternabil/go-api/models"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestGetForecast(t *testing.T) {
+	password := "Password123"
+	encryptedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
+	user := models.User{
+		UID:       uuid.New(),
+		Email:     "user@test.com",
+		FirstName: "test",
+		LastName:  "user",
+		Password:  string(encryptedPass),
+	}
+	food := models.Category{ID: uuid.New(), Name: "Food", UserID: user.UID}
+	subscriptions := models.Category{ID: uuid.New(), Name: "Subscriptions", UserID: user.UID}
+	transaction := func(title string, cat models.Category, amount int, date time.Time) models.Transaction {
+		t := models.Transaction{Title: title, CategoryID: cat.ID, Category: cat, Amount: amount, Negative: true, UserID: user.UID}
+		t.CreatedAt = date
+		return t
+	}
+	december := time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)
+	transactions := []models.Transaction{
+		transaction("streaming", subscriptions, 15, time.Date(2023, 10, 20, 0, 0, 0, 0, time.UTC)),
+		transaction("streaming", subscriptions, 15, time.Date(2023, 11, 20, 0, 0, 0, 0, time.UTC)),
+		transaction("salary", food, 5000, time.Date(2023, 11, 25, 0, 0, 0, 0, time.UTC)),
+	}
+	transactions[2].Negative = false
+	recurrings := []models.Recurring{
+		{Pattern: "streaming", Title: "streaming", Status: recurringConfirmed, Cadence: "monthly", Amount: 15,
+			NextDate: time.Date(2023, 11, 20, 0, 0, 0, 0, time.UTC), CategoryID: subscriptions.ID, UserID: user.UID},
+		{Pattern: "market", Title: "market", Status: recurringDismissed, Cadence: "weekly", Amount: 70,
+			NextDate: time.Date(2023, 12, 16, 0, 0, 0, 0, time.UTC), CategoryID: food.ID, UserID: user.UID},
+	}
+	// food is bought every saturday
+	for d := december.AddDate(0, 0, -90); d.Before(time.Date(2023, 12, 15, 0, 0, 0, 0, time.UTC)); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() == time.Saturday {
+			transactions = append(transactions, transaction("market", food, 70, d))
+		}
+	}
+	testCases := []struct {
+		name          string
+		param         string
+		buildStubs    func(store *mock_store.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "success",
+			param: "?date=2023-12-14T12:00:00Z",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetTransactionsDateRange(user.UID, december.AddDate(0, 0, -90), gomock.Any()).Times(1).Return(transactions, nil)
+				store.EXPECT().
+					GetRecurrings(user.UID).Times(1).Return(recurrings, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					Forecast forecast.Result `json:"forecast"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				// two saturdays so far, three more to come, and the streaming bill
+				require.Len(t, res.Forecast.Recurring, 1)
+				require.Equal(t, 20, res.Forecast.Recurring[0].ExpectedDate.Day())
+				require.Len(t, res.Forecast.Categories, 2)
+				require.Equal(t, food.ID, res.Forecast.Categories[0].CategoryID)
+				require.Equal(t, int64(140), res.Forecast.Categories[0].Spent)
+				require.Equal(t, int64(350), res.Forecast.Categories[0].Projected)
+				require.LessOrEqual(t, res.Forecast.Categories[0].Low, int64(350))
+				require.GreaterOrEqual(t, res.Forecast.Categories[0].High, int64(350))
+				require.Equal(t, int64(15), res.Forecast.Categories[1].Projected)
+				require.Equal(t, int64(365), res.Forecast.Total.Projected)
+			},
+		},
+		{
+			name:  "unconfirmed repeats are averaged",
+			param: "?date=2023-12-14T12:00:00Z",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetTransactionsDateRange(user.UID, december.AddDate(0, 0, -90), gomock.Any()).Times(1).Return(transactions, nil)
+				store.EXPECT().
+					GetRecurrings(user.UID).Times(1).Return([]models.Recurring{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					Forecast forecast.Result `json:"forecast"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Empty(t, res.Forecast.Recurring)
+				require.Equal(t, int64(350), res.Forecast.Categories[0].Projected)
+			},
+		},
+		{
+			name:  "lookback too long",
+			param: "?date=2023-12-14T12:00:00Z&lookback=731",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "period too long",
+			param: "?date=2023-12-14T12:00:00Z&start_date=2023-12-01T00:00:00Z&end_date=9999-12-31T00:00:00Z",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "yearly period",
+			param: "?date=2023-12-14T12:00:00Z&start_date=2023-01-01T00:00:00Z&end_date=2023-12-31T23:59:59Z",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetTransactionsDateRange(user.UID, gomock.Any(), gomock.Any()).Times(1).Return(transactions, nil)
+				store.EXPECT().
+					GetRecurrings(user.UID).Times(1).Return(recurrings, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "date outside the period",
+			param: "?date=2023-12-14T12:00:00Z&start_date=2023-11-01T00:00:00Z&end_date=2023-11-30T00:00:00Z",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "db error",
+			param: "",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetTransactionsDateRange(user.UID, gomock.Any(), gomock.Any()).Times(1).Return(nil, errors.New("db error"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:  "recurring db error",
+			param: "",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetTransactionsDateRange(user.UID, gomock.Any(), gomock.Any()).Times(1).Return(transactions, nil)
+				store.EXPECT().
+					GetRecurrings(user.UID).Times(1).Return(nil, errors.New("db error"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockStore := mock_store.NewMockStore(mockCtrl)
+			tt.buildStubs(mockStore)
+
+			server, _ := NewServer(mockStore, nil)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest("GET", fmt.Sprintf("/smart-account/api/v1/forecast%s", tt.param), nil)
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(recorder)
+		})
+	}
+}
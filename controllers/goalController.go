@@ -0,0 +1,264 @@
+package controllers
+
+import (
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/peternabil/go-api/models"
+)
+
+const (
+	daysPerMonth = 365.25 / 12
+	// maxProjectionMonths is how far out a completion date is still
+	// projected; goals saved for more slowly have none.
+	maxProjectionMonths = 100 * 12
+)
+
+type goalProgress struct {
+	Goal                models.Goal
+	Saved               int64
+	Remaining           int64
+	PercentComplete     float64
+	MonthlyRate         float64
+	RequiredMonthly     int64
+	ProjectedCompletion *time.Time
+	OnTrack             bool
+}
+
+// computeGoalProgress works out how far along the goal is at now. Linked
+// expenses count as contributions and linked income as withdrawals; the
+// historical rate runs from the goal's creation or its first contribution,
+// whichever came first.
+func computeGoalProgress(goal models.Goal, transactions []models.Transaction, now time.Time) goalProgress {
+	var saved int64
+	start := goal.CreatedAt
+	for _, t := range transactions {
+		if t.Negative {
+			saved += int64(t.Amount)
+		} else {
+			saved -= int64(t.Amount)
+		}
+		if start.IsZero() || t.CreatedAt.Before(start) {
+			start = t.CreatedAt
+		}
+	}
+	progress := goalProgress{Goal: goal, Saved: saved, Remaining: int64(goal.TargetAmount) - saved}
+	if progress.Remaining < 0 {
+		progress.Remaining = 0
+	}
+	if goal.TargetAmount > 0 {
+		progress.PercentComplete = math.Round(float64(saved)/float64(goal.TargetAmount)*10000) / 100
+	}
+	monthsElapsed := math.Max(now.Sub(start).Hours()/24/daysPerMonth, 1)
+	progress.MonthlyRate = math.Round(float64(saved)/monthsElapsed*100) / 100
+	if progress.Remaining == 0 {
+		progress.ProjectedCompletion = &now
+		progress.OnTrack = true
+		return progress
+	}
+	monthsLeft := goal.TargetDate.Sub(now).Hours() / 24 / daysPerMonth
+	if monthsLeft < 1 {
+		progress.RequiredMonthly = progress.Remaining
+	} else {
+		progress.RequiredMonthly = int64(math.Ceil(float64(progress.Remaining) / monthsLeft))
+	}
+	if progress.MonthlyRate > 0 {
+		months := math.Ceil(float64(progress.Remaining) / progress.MonthlyRate)
+		if months <= maxProjectionMonths {
+			projected := now.AddDate(0, int(months), 0)
+			progress.ProjectedCompletion = &projected
+			progress.OnTrack = !projected.After(goal.TargetDate)
+		}
+	}
+	return progress
+}
+
+// checkGoal parses an optional goal id and makes sure it belongs to the user.
+func (server *Server) checkGoal(c *gin.Context, user models.User, id string) (*uuid.UUID, error) {
+	goalId, err := parseOptionalUUID(id)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid goal uuid"})
+		return nil, err
+	}
+	if goalId != nil {
+		goal := models.Goal{ID: *goalId}
+		if _, err = server.store.GetGoal(user.UID, &goal); err != nil {
+			c.JSON(400, gin.H{"error": "goal not found"})
+			return nil, err
+		}
+	}
+	return goalId, nil
+}
+
+func (server *Server) bindGoal(c *gin.Context, user models.User, goal *models.Goal) error {
+	var body struct {
+		Name         string    `json:"Name" binding:"required,min=1"`
+		TargetAmount int       `json:"TargetAmount" binding:"required,min=1"`
+		TargetDate   time.Time `json:"TargetDate" binding:"required"`
+		Category     string    `json:"Category"`
+	}
+	err := c.BindJSON(&body)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return err
+	}
+	categoryID, err := parseOptionalUUID(body.Category)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid category uuid"})
+		return err
+	}
+	if categoryID != nil {
+		cat := models.Category{ID: *categoryID}
+		if _, err = server.store.GetCategory(user.UID, &cat); err != nil {
+			c.JSON(400, gin.H{"error": "category not found"})
+			return err
+		}
+	}
+	goal.Name = body.Name
+	goal.TargetAmount = body.TargetAmount
+	goal.TargetDate = body.TargetDate
+	goal.CategoryID = categoryID
+	goal.UserID = user.UID
+	return nil
+}
+
+func (server *Server) GoalIndex(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	goals, err := server.store.GetGoals(user.UID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no goals for this user"})
+		return
+	}
+	c.JSON(200, gin.H{
+		"goals": goals,
+	})
+}
+
+func (server *Server) GoalFind(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	gId, uuidErr := uuid.Parse(c.Param("id"))
+	if uuidErr != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "invalid uuid"})
+		return
+	}
+	goal := models.Goal{ID: gId}
+	res, err := server.store.GetGoal(user.UID, &goal)
+	if err != nil {
+		c.Status(404)
+		return
+	}
+	c.JSON(200, gin.H{
+		"goal": res,
+	})
+}
+
+func (server *Server) GoalCreate(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	goal := models.Goal{}
+	if err := server.bindGoal(c, user, &goal); err != nil {
+		return
+	}
+	res, err := server.store.CreateGoal(&goal)
+	if err != nil {
+		c.Status(400)
+		return
+	}
+	c.JSON(200, gin.H{
+		"goal": res,
+	})
+}
+
+func (server *Server) GoalEdit(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	gId, uuidErr := uuid.Parse(c.Param("id"))
+	if uuidErr != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "invalid uuid"})
+		return
+	}
+	goal := models.Goal{ID: gId}
+	res, err := server.store.GetGoal(user.UID, &goal)
+	if err != nil {
+		c.Status(404)
+		return
+	}
+	goal = res
+	if err = server.bindGoal(c, user, &goal); err != nil {
+		return
+	}
+	res, err = server.store.EditGoal(&goal)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	c.JSON(200, gin.H{
+		"goal": res,
+	})
+}
+
+func (server *Server) GoalDelete(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	gId, uuidErr := uuid.Parse(c.Param("id"))
+	if uuidErr != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "invalid uuid"})
+		return
+	}
+	goal := models.Goal{ID: gId, UserID: user.UID}
+	res := server.store.DeleteGoal(&goal)
+	if res != nil {
+		c.Status(400)
+		return
+	}
+	c.JSON(200, gin.H{
+		"goal": goal,
+	})
+}
+
+// GoalProgress reports the progress of a single goal.
+func (server *Server) GoalProgress(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	gId, uuidErr := uuid.Parse(c.Param("id"))
+	if uuidErr != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "invalid uuid"})
+		return
+	}
+	goal := models.Goal{ID: gId}
+	res, err := server.store.GetGoal(user.UID, &goal)
+	if err != nil {
+		c.Status(404)
+		return
+	}
+	transactions, err := server.store.GetGoalTransactions(user.UID, &res)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	c.JSON(200, gin.H{
+		"progress": computeGoalProgress(res, transactions, time.Now()),
+	})
+}
+
+// GoalIndexProgress reports the progress of every goal the user has.
+func (server *Server) GoalIndexProgress(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	goals, err := server.store.GetGoals(user.UID)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	now := time.Now()
+	progress := []goalProgress{}
+	for i := range goals {
+		transactions, err := server.store.GetGoalTransactions(user.UID, &goals[i])
+		if err != nil {
+			c.Status(500)
+			return
+		}
+		progress = append(progress, computeGoalProgress(goals[i], transactions, now))
+	}
+	c.JSON(200, gin.H{
+		"progress": progress,
+	})
+}
@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mock_store "github.com/peternabil/go-api/mocks"
+	"github.com/peternabil/go-api/models"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestGoalProgress(t *testing.T) {
+	password := "Password123"
+	encryptedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
+	user := models.User{
+		UID:       uuid.New(),
+		Email:     "user@test.com",
+		FirstName: "test",
+		LastName:  "user",
+		Password:  string(encryptedPass),
+	}
+	now := time.Now()
+	goal := models.Goal{
+		ID:           uuid.New(),
+		Name:         "car",
+		TargetAmount: 12000,
+		TargetDate:   now.AddDate(1, 0, 0),
+		UserID:       user.UID,
+	}
+	goal.CreatedAt = now.AddDate(0, -4, 0)
+	onTrack := []models.Transaction{
+		{Amount: 2000, Negative: true, GoalID: &goal.ID},
+		{Amount: 2000, Negative: true, GoalID: &goal.ID},
+		{Amount: 1000, Negative: false, GoalID: &goal.ID},
+		{Amount: 1000, Negative: true, GoalID: &goal.ID},
+	}
+	behind := []models.Transaction{
+		{Amount: 400, Negative: true, GoalID: &goal.ID},
+	}
+	for i := range onTrack {
+		onTrack[i].CreatedAt = now.AddDate(0, i-4, 0)
+	}
+	behind[0].CreatedAt = now.AddDate(0, -1, 0)
+	slow := []models.Transaction{
+		{Amount: 50, Negative: true, GoalID: &goal.ID},
+	}
+	slow[0].CreatedAt = now.AddDate(0, -10, 0)
+	bigGoal := goal
+	bigGoal.TargetAmount = 20000
+	testCases := []struct {
+		name          string
+		id            string
+		buildStubs    func(store *mock_store.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "on track",
+			id:   goal.ID.String(),
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetGoal(user.UID, gomock.Any()).Times(1).Return(goal, nil)
+				store.EXPECT().
+					GetGoalTransactions(user.UID, gomock.Any()).Times(1).Return(onTrack, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					Progress goalProgress `json:"progress"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, int64(4000), res.Progress.Saved)
+				require.Equal(t, int64(8000), res.Progress.Remaining)
+				require.InDelta(t, 1000, res.Progress.MonthlyRate, 20)
+				require.InDelta(t, 667, res.Progress.RequiredMonthly, 5)
+				require.NotNil(t, res.Progress.ProjectedCompletion)
+				require.True(t, res.Progress.OnTrack)
+			},
+		},
+		{
+			name: "behind",
+			id:   goal.ID.String(),
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetGoal(user.UID, gomock.Any()).Times(1).Return(goal, nil)
+				store.EXPECT().
+					GetGoalTransactions(user.UID, gomock.Any()).Times(1).Return(behind, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					Progress goalProgress `json:"progress"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, int64(11600), res.Progress.Remaining)
+				require.True(t, res.Progress.ProjectedCompletion.After(goal.TargetDate))
+				require.False(t, res.Progress.OnTrack)
+			},
+		},
+		{
+			name: "too slow to project",
+			id:   goal.ID.String(),
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetGoal(user.UID, gomock.Any()).Times(1).Return(bigGoal, nil)
+				store.EXPECT().
+					GetGoalTransactions(user.UID, gomock.Any()).Times(1).Return(slow, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					Progress goalProgress `json:"progress"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, int64(19950), res.Progress.Remaining)
+				require.InDelta(t, 5, res.Progress.MonthlyRate, 0.1)
+				require.Nil(t, res.Progress.ProjectedCompletion)
+				require.False(t, res.Progress.OnTrack)
+			},
+		},
+		{
+			name: "not found",
+			id:   goal.ID.String(),
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetGoal(user.UID, gomock.Any()).Times(1).Return(models.Goal{}, errors.New("not found"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockStore := mock_store.NewMockStore(mockCtrl)
+			tt.buildStubs(mockStore)
+
+			server, _ := NewServer(mockStore, nil)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest("GET", "/smart-account/api/v1/goal/"+tt.id+"/progress", nil)
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(recorder)
+		})
+	}
+}
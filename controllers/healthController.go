@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// healthMonth is one month of the financial health history. Shares are of
+// the month's expenses and SavingsRate is left out without income.
+type healthMonth struct {
+	Month             time.Time
+	Label             string
+	Income            int64
+	Expense           int64
+	Net               int64
+	SavingsRate       *float64
+	HighPriority      int64
+	LowPriority       int64
+	HighPriorityShare *float64
+}
+
+// ratio returns part/whole rounded to four decimals, or nil when whole is
+// not positive.
+func ratio(part, whole int64) *float64 {
+	if whole <= 0 {
+		return nil
+	}
+	r := math.Round(float64(part)/float64(whole)*10000) / 10000
+	return &r
+}
+
+// GetHealth reports headline numbers over the months complete months (6 by
+// default) before the month of date (now by default): savings rate, average
+// monthly burn, runway of the current balance at that burn and the share of
+// expenses at priority levels of high_level (6 by default) and above, with a
+// month-by-month history.
+func (server *Server) GetHealth(c *gin.Context) {
+	loc, err := requestLocation(c)
+	if err != nil {
+		return
+	}
+	asOf := time.Now().In(loc)
+	if dateStr := c.Query("date"); dateStr != "" {
+		asOf, err = parseDate(dateStr, loc)
+		if err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	months, err := strconv.Atoi(c.DefaultQuery("months", "6"))
+	if err != nil || months <= 0 || months > 120 {
+		c.JSON(400, gin.H{"error": "months must be a number between 1 and 120"})
+		return
+	}
+	highLevel, err := strconv.Atoi(c.DefaultQuery("high_level", "6"))
+	if err != nil || highLevel < 1 || highLevel > 10 {
+		c.JSON(400, gin.H{"error": "high_level must be a number between 1 and 10"})
+		return
+	}
+	b := bucketer{granularity: granularityMonth}
+	endDate := b.start(asOf)
+	startDate := endDate.AddDate(0, -months, 0)
+	endDate = endDate.Add(-time.Second)
+	user := server.store.GetUserFromToken(c)
+	spendings, err := server.store.GetMonthlySpendingLevel(user.UID, startDate, endDate)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	balance, err := server.store.GetBalance(user.UID, asOf)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+
+	history := []healthMonth{}
+	index := map[time.Time]int{}
+	for t := startDate; t.Before(endDate); t = b.next(t) {
+		index[t] = len(history)
+		history = append(history, healthMonth{Month: t, Label: b.label(t)})
+	}
+	for _, sp := range spendings {
+		i, ok := index[time.Date(sp.Date.Year(), sp.Date.Month(), 1, 0, 0, 0, 0, loc)]
+		if !ok {
+			continue
+		}
+		switch {
+		case !sp.Negative:
+			history[i].Income += sp.Total
+		case sp.Level >= highLevel:
+			history[i].Expense += sp.Total
+			history[i].HighPriority += sp.Total
+		default:
+			history[i].Expense += sp.Total
+			history[i].LowPriority += sp.Total
+		}
+	}
+	var income, expense, high, low int64
+	for i := range history {
+		m := &history[i]
+		m.Net = m.Income - m.Expense
+		m.SavingsRate = ratio(m.Net, m.Income)
+		m.HighPriorityShare = ratio(m.HighPriority, m.Expense)
+		income += m.Income
+		expense += m.Expense
+		high += m.HighPriority
+		low += m.LowPriority
+	}
+	burn := float64(expense) / float64(months)
+	var runway *float64
+	if burn > 0 {
+		r := math.Round(math.Max(float64(balance), 0)/burn*10) / 10
+		runway = &r
+	}
+	c.JSON(200, gin.H{
+		"start_date":          startDate,
+		"end_date":            endDate,
+		"savings_rate":        ratio(income-expense, income),
+		"average_burn":        int64(math.Round(burn)),
+		"balance":             balance,
+		"runway_months":       runway,
+		"high_priority_share": ratio(high, expense),
+		"low_priority_share":  ratio(low, expense),
+		"history":             history,
+	})
+}
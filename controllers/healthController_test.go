@@ -0,0 +1,163 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mock_store "github.com/peternabil/go-api/mocks"
+	"github.com/peternabil/go-api/models"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestGetHealth(t *testing.T) {
+	password := "Password123"
+	encryptedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
+	user := models.User{
+		UID:       uuid.New(),
+		Email:     "user@test.com",
+		FirstName: "test",
+		LastName:  "user",
+		Password:  string(encryptedPass),
+	}
+	october := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	november := time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC)
+	spendings := []models.SpendingPriority{
+		{Date: october, Negative: false, Total: 4000},
+		{Date: october, Negative: true, Level: 10, Total: 2000},
+		{Date: october, Negative: true, Level: 2, Total: 1000},
+		{Date: november, Negative: true, Level: 7, Total: 3000},
+	}
+	testCases := []struct {
+		name          string
+		param         string
+		buildStubs    func(store *mock_store.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "success",
+			param: "?date=2023-12-10T00:00:00Z&months=2",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetMonthlySpendingLevel(user.UID, october, time.Date(2023, 11, 30, 23, 59, 59, 0, time.UTC)).Times(1).Return(spendings, nil)
+				store.EXPECT().
+					GetBalance(user.UID, gomock.Any()).Times(1).Return(int64(6000), nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					SavingsRate       *float64      `json:"savings_rate"`
+					AverageBurn       int64         `json:"average_burn"`
+					RunwayMonths      *float64      `json:"runway_months"`
+					HighPriorityShare *float64      `json:"high_priority_share"`
+					LowPriorityShare  *float64      `json:"low_priority_share"`
+					History           []healthMonth `json:"history"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, -0.5, *res.SavingsRate)
+				require.Equal(t, int64(3000), res.AverageBurn)
+				require.Equal(t, 2.0, *res.RunwayMonths)
+				require.Equal(t, 0.8333, *res.HighPriorityShare)
+				require.Equal(t, 0.1667, *res.LowPriorityShare)
+				require.Len(t, res.History, 2)
+				require.Equal(t, "2023-10", res.History[0].Label)
+				require.Equal(t, 0.25, *res.History[0].SavingsRate)
+				require.Equal(t, int64(2000), res.History[0].HighPriority)
+				require.Nil(t, res.History[1].SavingsRate)
+				require.Equal(t, int64(-3000), res.History[1].Net)
+				require.Equal(t, 1.0, *res.History[1].HighPriorityShare)
+			},
+		},
+		{
+			name:  "no spending",
+			param: "?date=2023-12-10T00:00:00Z",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetMonthlySpendingLevel(user.UID, time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC), gomock.Any()).Times(1).Return([]models.SpendingPriority{}, nil)
+				store.EXPECT().
+					GetBalance(user.UID, gomock.Any()).Times(1).Return(int64(0), nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					SavingsRate  *float64      `json:"savings_rate"`
+					RunwayMonths *float64      `json:"runway_months"`
+					History      []healthMonth `json:"history"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Nil(t, res.SavingsRate)
+				require.Nil(t, res.RunwayMonths)
+				require.Len(t, res.History, 6)
+			},
+		},
+		{
+			name:  "invalid months",
+			param: "?months=0",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "invalid high level",
+			param: "?high_level=11",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "db error",
+			param: "",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetMonthlySpendingLevel(user.UID, gomock.Any(), gomock.Any()).Times(1).Return(nil, errors.New("db error"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockStore := mock_store.NewMockStore(mockCtrl)
+			tt.buildStubs(mockStore)
+
+			server, _ := NewServer(mockStore, nil)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest("GET", fmt.Sprintf("/smart-account/api/v1/financial-health%s", tt.param), nil)
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/peternabil/go-api/models"
+)
+
+// weekdayPattern is the spending of one day of the week, 0 being Sunday.
+type weekdayPattern struct {
+	Weekday int
+	Name    string
+	Total   int64
+	Count   int64
+}
+
+// hourPattern is the spending of one hour of the day.
+type hourPattern struct {
+	Hour  int
+	Total int64
+	Count int64
+}
+
+// GetPatterns sums spending in the period by day of the week and by hour of
+// the day, read off the time of each transaction in the user's time zone,
+// optionally for one category or priority. Besides the two breakdowns it
+// returns the full weekday by hour grid and its busiest cell.
+func (server *Server) GetPatterns(c *gin.Context) {
+	var startDate, endDate time.Time
+	var negative bool
+	err := setDates(c, &startDate, &endDate)
+	if err != nil {
+		return
+	}
+	err = setNegative(c, &negative)
+	if err != nil {
+		return
+	}
+	categoryID, err := parseOptionalUUID(c.Query("category"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid category uuid"})
+		return
+	}
+	priorityID, err := parseOptionalUUID(c.Query("priority"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid priority uuid"})
+		return
+	}
+	user := server.store.GetUserFromToken(c)
+	patterns, err := server.store.GetSpendingPatterns(user.UID, startDate, endDate, negative, categoryID, priorityID)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	weekdays := make([]weekdayPattern, 7)
+	for i := range weekdays {
+		weekdays[i] = weekdayPattern{Weekday: i, Name: time.Weekday(i).String()}
+	}
+	hours := make([]hourPattern, 24)
+	for i := range hours {
+		hours[i] = hourPattern{Hour: i}
+	}
+	grid := [7][24]models.SpendingPattern{}
+	for d := range grid {
+		for h := range grid[d] {
+			grid[d][h] = models.SpendingPattern{Weekday: d, Hour: h}
+		}
+	}
+	var peak *models.SpendingPattern
+	for _, p := range patterns {
+		if p.Weekday < 0 || p.Weekday > 6 || p.Hour < 0 || p.Hour > 23 {
+			continue
+		}
+		weekdays[p.Weekday].Total += p.Total
+		weekdays[p.Weekday].Count += p.Count
+		hours[p.Hour].Total += p.Total
+		hours[p.Hour].Count += p.Count
+		grid[p.Weekday][p.Hour] = p
+		if peak == nil || p.Total > peak.Total {
+			peak = &grid[p.Weekday][p.Hour]
+		}
+	}
+	c.JSON(200, gin.H{
+		"start_date": startDate,
+		"end_date":   endDate,
+		"weekdays":   weekdays,
+		"hours":      hours,
+		"grid":       grid,
+		"peak":       peak,
+	})
+}
@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mock_store "github.com/peternabil/go-api/mocks"
+	"github.com/peternabil/go-api/models"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestGetPatterns(t *testing.T) {
+	password := "Password123"
+	encryptedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
+	user := models.User{
+		UID:       uuid.New(),
+		Email:     "user@test.com",
+		FirstName: "test",
+		LastName:  "user",
+		Password:  string(encryptedPass),
+	}
+	categoryID := uuid.New()
+	patterns := []models.SpendingPattern{
+		{Weekday: 1, Hour: 9, Total: 300, Count: 2},
+		{Weekday: 5, Hour: 21, Total: 1200, Count: 4},
+		{Weekday: 5, Hour: 22, Total: 800, Count: 3},
+	}
+	type response struct {
+		Weekdays []weekdayPattern              `json:"weekdays"`
+		Hours    []hourPattern                 `json:"hours"`
+		Grid     [7][24]models.SpendingPattern `json:"grid"`
+		Peak     *models.SpendingPattern       `json:"peak"`
+	}
+	testCases := []struct {
+		name          string
+		param         string
+		buildStubs    func(store *mock_store.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "success",
+			param: fmt.Sprintf("?start_date=2023-10-01T00:00:00Z&end_date=2023-10-31T23:59:59Z&negative=true&category=%s", categoryID),
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetSpendingPatterns(user.UID, gomock.Any(), gomock.Any(), true, &categoryID, nil).Times(1).Return(patterns, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res response
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Len(t, res.Weekdays, 7)
+				require.Equal(t, "Friday", res.Weekdays[5].Name)
+				require.Equal(t, int64(2000), res.Weekdays[5].Total)
+				require.Equal(t, int64(7), res.Weekdays[5].Count)
+				require.Len(t, res.Hours, 24)
+				require.Equal(t, int64(1200), res.Hours[21].Total)
+				require.Equal(t, int64(300), res.Hours[9].Total)
+				require.Equal(t, int64(800), res.Grid[5][22].Total)
+				require.Equal(t, 22, res.Grid[5][22].Hour)
+				require.Equal(t, int64(0), res.Grid[0][0].Total)
+				require.Equal(t, 5, res.Peak.Weekday)
+				require.Equal(t, 21, res.Peak.Hour)
+			},
+		},
+		{
+			name:  "user time zone",
+			param: "?start_date=2023-10-01T00:00:00&end_date=2023-10-31T23:59:59&negative=true&tz=Africa/Cairo",
+			buildStubs: func(store *mock_store.MockStore) {
+				loc, _ := time.LoadLocation("Africa/Cairo")
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetSpendingPatterns(user.UID, time.Date(2023, 10, 1, 0, 0, 0, 0, loc), time.Date(2023, 10, 31, 23, 59, 59, 0, loc), true, nil, nil).Times(1).Return([]models.SpendingPattern{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res response
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Nil(t, res.Peak)
+				require.Len(t, res.Weekdays, 7)
+			},
+		},
+		{
+			name:  "invalid priority",
+			param: "?range=this_month&negative=true&priority=high",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "missing negative",
+			param: "?range=this_month",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "db error",
+			param: "?range=this_month&negative=true",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetSpendingPatterns(user.UID, gomock.Any(), gomock.Any(), true, nil, nil).Times(1).Return(nil, errors.New("db error"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockStore := mock_store.NewMockStore(mockCtrl)
+			tt.buildStubs(mockStore)
+
+			server, _ := NewServer(mockStore, nil)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest("GET", fmt.Sprintf("/smart-account/api/v1/patterns%s", tt.param), nil)
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(recorder)
+		})
+	}
+}
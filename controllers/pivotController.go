@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/peternabil/go-api/models"
+)
+
+const (
+	rowsCategory = "category"
+	rowsPriority = "priority"
+)
+
+type pivotColumn struct {
+	Date  time.Time
+	Label string
+	Total int64
+}
+
+// pivotCell is one entry of the matrix with its change against the cell to
+// its left; the changes are left out in the first column.
+type pivotCell struct {
+	Total         int64
+	Change        *int64
+	PercentChange *float64
+}
+
+type pivotRow struct {
+	ID    uuid.UUID
+	Name  string
+	Total int64
+	Cells []pivotCell
+}
+
+// buildPivot lays the cells out as rows by the bucket columns covering
+// startDate to endDate, biggest rows first.
+func buildPivot(cells []models.PivotCell, startDate, endDate time.Time, b bucketer) ([]pivotColumn, []pivotRow) {
+	columns := []pivotColumn{}
+	columnIndex := map[time.Time]int{}
+	for t := b.start(startDate); !t.After(endDate); t = b.next(t) {
+		columnIndex[t] = len(columns)
+		columns = append(columns, pivotColumn{Date: t, Label: b.label(t)})
+	}
+	rows := []pivotRow{}
+	rowIndex := map[uuid.UUID]int{}
+	for _, cell := range cells {
+		date := time.Date(cell.Date.Year(), cell.Date.Month(), cell.Date.Day(), 0, 0, 0, 0, startDate.Location())
+		col, ok := columnIndex[b.start(date)]
+		if !ok {
+			continue
+		}
+		i, ok := rowIndex[cell.ID]
+		if !ok {
+			i = len(rows)
+			rowIndex[cell.ID] = i
+			rows = append(rows, pivotRow{ID: cell.ID, Name: cell.Name, Cells: make([]pivotCell, len(columns))})
+		}
+		rows[i].Cells[col].Total += cell.Total
+		rows[i].Total += cell.Total
+		columns[col].Total += cell.Total
+	}
+	for _, row := range rows {
+		for i := 1; i < len(row.Cells); i++ {
+			previous := row.Cells[i-1].Total
+			change := row.Cells[i].Total - previous
+			row.Cells[i].Change = &change
+			if previous != 0 {
+				percent := math.Round(float64(change)/float64(previous)*10000) / 100
+				row.Cells[i].PercentChange = &percent
+			}
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].Total > rows[j].Total
+	})
+	return columns, rows
+}
+
+// GetPivot returns spending as a matrix of categories or priorities (the rows
+// query parameter) by day, week or month columns, with row and column totals
+// and the change of every cell against the previous column. max is the
+// largest cell, for scaling a heatmap.
+func (server *Server) GetPivot(c *gin.Context) {
+	var startDate, endDate time.Time
+	var negative bool
+	err := setDates(c, &startDate, &endDate)
+	if err != nil {
+		return
+	}
+	err = setNegative(c, &negative)
+	if err != nil {
+		return
+	}
+	rows := c.DefaultQuery("rows", rowsCategory)
+	if rows != rowsCategory && rows != rowsPriority {
+		c.JSON(400, gin.H{"error": "rows must be category or priority"})
+		return
+	}
+	var b bucketer
+	err = setBucketer(c, &b, granularityMonth)
+	if err != nil {
+		return
+	}
+	err = checkBuckets(c, b, startDate, endDate)
+	if err != nil {
+		return
+	}
+	// weeks only line up with date_trunc when they start on Monday
+	unit := b.granularity
+	if unit == granularityWeek && b.weekStart != time.Monday {
+		unit = granularityDay
+	}
+	user := server.store.GetUserFromToken(c)
+	cells, err := server.store.GetSpendingPivot(user.UID, startDate, endDate, negative, rows, unit)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	columns, matrix := buildPivot(cells, startDate, endDate, b)
+	var total, highest int64
+	for _, col := range columns {
+		total += col.Total
+	}
+	for _, row := range matrix {
+		for _, cell := range row.Cells {
+			if cell.Total > highest {
+				highest = cell.Total
+			}
+		}
+	}
+	c.JSON(200, gin.H{
+		"start_date": startDate,
+		"end_date":   endDate,
+		"columns":    columns,
+		"rows":       matrix,
+		"total":      total,
+		"max":        highest,
+	})
+}
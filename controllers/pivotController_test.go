@@ -0,0 +1,169 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mock_store "github.com/peternabil/go-api/mocks"
+	"github.com/peternabil/go-api/models"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestGetPivot(t *testing.T) {
+	password := "Password123"
+	encryptedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
+	user := models.User{
+		UID:       uuid.New(),
+		Email:     "user@test.com",
+		FirstName: "test",
+		LastName:  "user",
+		Password:  string(encryptedPass),
+	}
+	groceries := uuid.New()
+	rent := uuid.New()
+	type response struct {
+		Columns []pivotColumn `json:"columns"`
+		Rows    []pivotRow    `json:"rows"`
+		Total   int64         `json:"total"`
+		Max     int64         `json:"max"`
+	}
+	testCases := []struct {
+		name          string
+		param         string
+		buildStubs    func(store *mock_store.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "categories by month",
+			param: "?start_date=2023-09-01T00:00:00Z&end_date=2023-11-30T23:59:59Z&negative=true",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetSpendingPivot(user.UID, gomock.Any(), gomock.Any(), true, rowsCategory, granularityMonth).Times(1).Return([]models.PivotCell{
+					{Date: time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC), ID: groceries, Name: "Groceries", Total: 200},
+					{Date: time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC), ID: rent, Name: "Rent", Total: 1000},
+					{Date: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), ID: groceries, Name: "Groceries", Total: 300},
+					{Date: time.Date(2023, 11, 1, 0, 0, 0, 0, time.UTC), ID: rent, Name: "Rent", Total: 1000},
+				}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res response
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Len(t, res.Columns, 3)
+				require.Equal(t, "2023-10", res.Columns[1].Label)
+				require.Equal(t, int64(1200), res.Columns[0].Total)
+				require.Equal(t, int64(2500), res.Total)
+				require.Equal(t, int64(1000), res.Max)
+				require.Len(t, res.Rows, 2)
+				require.Equal(t, rent, res.Rows[0].ID)
+				require.Equal(t, int64(2000), res.Rows[0].Total)
+				require.Nil(t, res.Rows[0].Cells[0].Change)
+				require.Equal(t, int64(-1000), *res.Rows[0].Cells[1].Change)
+				require.Equal(t, -100.0, *res.Rows[0].Cells[1].PercentChange)
+				require.Nil(t, res.Rows[0].Cells[2].PercentChange)
+				require.Equal(t, 50.0, *res.Rows[1].Cells[1].PercentChange)
+			},
+		},
+		{
+			name:  "priorities by sunday weeks",
+			param: "?start_date=2023-11-05T00:00:00Z&end_date=2023-11-18T23:59:59Z&negative=true&rows=priority&granularity=week&week_start=sunday",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetSpendingPivot(user.UID, gomock.Any(), gomock.Any(), true, rowsPriority, granularityDay).Times(1).Return([]models.PivotCell{
+					{Date: time.Date(2023, 11, 6, 0, 0, 0, 0, time.UTC), ID: rent, Name: "Essential", Total: 10},
+					{Date: time.Date(2023, 11, 11, 0, 0, 0, 0, time.UTC), ID: rent, Name: "Essential", Total: 20},
+					{Date: time.Date(2023, 11, 12, 0, 0, 0, 0, time.UTC), ID: rent, Name: "Essential", Total: 5},
+				}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res response
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Len(t, res.Columns, 2)
+				require.Equal(t, int64(30), res.Rows[0].Cells[0].Total)
+				require.Equal(t, int64(5), res.Rows[0].Cells[1].Total)
+			},
+		},
+		{
+			name:  "invalid rows",
+			param: "?start_date=2023-09-01T00:00:00Z&end_date=2023-11-30T23:59:59Z&negative=true&rows=merchant",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "too many buckets",
+			param: "?start_date=2000-01-01T00:00:00Z&end_date=2023-12-31T23:59:59Z&negative=true&granularity=day",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "invalid granularity",
+			param: "?start_date=2023-09-01T00:00:00Z&end_date=2023-11-30T23:59:59Z&negative=true&granularity=hour",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "db error",
+			param: "?start_date=2023-09-01T00:00:00Z&end_date=2023-11-30T23:59:59Z&negative=true",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetSpendingPivot(user.UID, gomock.Any(), gomock.Any(), true, rowsCategory, granularityMonth).Times(1).Return([]models.PivotCell{}, errors.New("db error"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockStore := mock_store.NewMockStore(mockCtrl)
+			tt.buildStubs(mockStore)
+
+			server, _ := NewServer(mockStore, nil)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest("GET", fmt.Sprintf("/smart-account/api/v1/pivot%s", tt.param), nil)
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(recorder)
+		})
+	}
+}
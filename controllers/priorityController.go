@@ -102,3 +102,53 @@ func (server *Server) PriorityDelete(c *gin.Context) {
 		"priority": priority,
 	})
 }
+
+// PriorityMerge moves every transaction, rule and budget of the priority in
+// the path to the target priority and deletes it.
+func (server *Server) PriorityMerge(c *gin.Context) {
+	var body struct {
+		Target string `json:"Target" binding:"required"`
+	}
+	err := c.BindJSON(&body)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	sourceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "invalid uuid"})
+		return
+	}
+	targetID, err := uuid.Parse(body.Target)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid target uuid"})
+		return
+	}
+	if sourceID == targetID {
+		c.JSON(400, gin.H{"error": "cannot merge a priority into itself"})
+		return
+	}
+	user := server.store.GetUserFromToken(c)
+	source := models.Priority{ID: sourceID}
+	source, err = server.store.GetPriority(user.UID, &source)
+	if err != nil {
+		c.Status(404)
+		return
+	}
+	target := models.Priority{ID: targetID}
+	target, err = server.store.GetPriority(user.UID, &target)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "target priority not found"})
+		return
+	}
+	moved, err := server.store.MergePriorities(user.UID, &source, &target)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "could not merge priorities"})
+		return
+	}
+	server.classifiers.Reset(user.UID)
+	c.JSON(200, gin.H{
+		"priority": target,
+		"moved":    moved,
+	})
+}
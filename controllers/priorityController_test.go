@@ -466,3 +466,124 @@ func TestDeletePriorty(t *testing.T) {
 		})
 	}
 }
+
+func TestMergePriority(t *testing.T) {
+	password := "Password123"
+	encryptedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
+
+	user := models.User{
+		UID:       uuid.New(),
+		Email:     "user@test.com",
+		FirstName: "test",
+		LastName:  "user",
+		Password:  string(encryptedPass),
+	}
+	sourceId := uuid.New()
+	targetId := uuid.New()
+	findPriority := func(id uuid.UUID, prio *models.Priority) (models.Priority, error) {
+		return *prio, nil
+	}
+	testCases := []struct {
+		name          string
+		params        string
+		body          gin.H
+		buildStubs    func(store *mock_store.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "success",
+			params: sourceId.String(),
+			body:   gin.H{"Target": targetId.String()},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetPriority(user.UID, gomock.Any()).Times(2).DoAndReturn(findPriority)
+				store.EXPECT().
+					MergePriorities(user.UID, gomock.Any(), gomock.Any()).Times(1).DoAndReturn(func(id uuid.UUID, source, target *models.Priority) (models.MergeResult, error) {
+					require.Equal(t, sourceId, source.ID)
+					require.Equal(t, targetId, target.ID)
+					return models.MergeResult{Transactions: 4, Rules: 1}, nil
+				})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					Moved models.MergeResult `json:"moved"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, int64(4), res.Moved.Transactions)
+			},
+		},
+		{
+			name:   "merge into itself",
+			params: sourceId.String(),
+			body:   gin.H{"Target": sourceId.String()},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "target not found",
+			params: sourceId.String(),
+			body:   gin.H{"Target": targetId.String()},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetPriority(user.UID, gomock.Any()).Times(1).DoAndReturn(findPriority)
+				store.EXPECT().
+					GetPriority(user.UID, gomock.Any()).Times(1).Return(models.Priority{}, errors.New("not found"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "db error",
+			params: sourceId.String(),
+			body:   gin.H{"Target": targetId.String()},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetPriority(user.UID, gomock.Any()).Times(2).DoAndReturn(findPriority)
+				store.EXPECT().
+					MergePriorities(user.UID, gomock.Any(), gomock.Any()).Times(1).Return(models.MergeResult{}, errors.New("db error"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockStore := mock_store.NewMockStore(mockCtrl)
+			tt.buildStubs(mockStore)
+
+			server, _ := NewServer(mockStore, nil)
+			recorder := httptest.NewRecorder()
+
+			body, err := json.Marshal(tt.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest("POST", fmt.Sprintf("/smart-account/api/v1/priority/%s/merge", tt.params), bytes.NewReader(body))
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/peternabil/go-api/models"
+)
+
+// rangeNames lists the presets accepted by the range query parameter besides
+// last_<n>_days, e.g. last_30_days.
+var rangeNames = []string{
+	"today", "yesterday",
+	"this_week", "last_week",
+	"this_month", "last_month",
+	"this_quarter", "last_quarter",
+	"this_year", "last_year", "ytd",
+}
+
+// fiscalMonth returns the start of the month containing t when months start
+// on the given day.
+func fiscalMonth(t time.Time, startDay int) time.Time {
+	if startDay < 1 {
+		startDay = 1
+	}
+	month := time.Date(t.Year(), t.Month(), startDay, 0, 0, 0, 0, t.Location())
+	if t.Day() < startDay {
+		month = month.AddDate(0, -1, 0)
+	}
+	return month
+}
+
+// resolveRange turns a named preset into an absolute range around now, with
+// months starting on fiscalStart and weeks on weekStart. The range ends on
+// the last second of its final day.
+func resolveRange(name string, now time.Time, weekStart time.Weekday, fiscalStart int) (time.Time, time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	weeks := bucketer{granularity: granularityWeek, weekStart: weekStart}
+	quarters := bucketer{granularity: granularityQuarter}
+	years := bucketer{granularity: granularityYear}
+	var start, next time.Time
+	switch name {
+	case "today":
+		start, next = today, today.AddDate(0, 0, 1)
+	case "yesterday":
+		start, next = today.AddDate(0, 0, -1), today
+	case "this_week":
+		start = weeks.start(today)
+		next = weeks.next(start)
+	case "last_week":
+		next = weeks.start(today)
+		start = next.AddDate(0, 0, -7)
+	case "this_month":
+		start = fiscalMonth(today, fiscalStart)
+		next = start.AddDate(0, 1, 0)
+	case "last_month":
+		next = fiscalMonth(today, fiscalStart)
+		start = next.AddDate(0, -1, 0)
+	case "this_quarter":
+		start = quarters.start(today)
+		next = quarters.next(start)
+	case "last_quarter":
+		next = quarters.start(today)
+		start = next.AddDate(0, -3, 0)
+	case "this_year":
+		start = years.start(today)
+		next = years.next(start)
+	case "last_year":
+		next = years.start(today)
+		start = next.AddDate(-1, 0, 0)
+	case "ytd":
+		start, next = years.start(today), today.AddDate(0, 0, 1)
+	default:
+		days := strings.TrimSuffix(strings.TrimPrefix(name, "last_"), "_days")
+		n, err := strconv.Atoi(days)
+		if !strings.HasPrefix(name, "last_") || !strings.HasSuffix(name, "_days") || err != nil || n <= 0 {
+			return now, now, fmt.Errorf("range must be one of %s or last_<n>_days", strings.Join(rangeNames, ", "))
+		}
+		start, next = today.AddDate(0, 0, 1-n), today.AddDate(0, 0, 1)
+	}
+	return start, next.Add(-time.Second), nil
+}
+
+// setRange resolves the range query parameter in the request's time zone
+// using the user's fiscal month start.
+func setRange(c *gin.Context, loc *time.Location, startDate, endDate *time.Time) error {
+	weekStart, err := weekStartParam(c)
+	if err != nil {
+		return err
+	}
+	var fiscalStart int
+	if user, ok := c.Value("user").(models.User); ok {
+		fiscalStart = user.FiscalMonthStart
+	}
+	*startDate, *endDate, err = resolveRange(c.Query("range"), time.Now().In(loc), weekStart, fiscalStart)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/peternabil/go-api/models"
+	"github.com/peternabil/go-api/recurring"
+)
+
+const (
+	recurringConfirmed = "confirmed"
+	recurringDismissed = "dismissed"
+)
+
+// detectRecurring runs subscription detection over the user's history and
+// leaves out the charges the user has already confirmed or dismissed.
+func (server *Server) detectRecurring(user models.User, loc *time.Location) ([]recurring.Subscription, error) {
+	transactions, err := server.store.GetAllTransactions(user.UID)
+	if err != nil {
+		return nil, err
+	}
+	decided, err := server.store.GetRecurrings(user.UID)
+	if err != nil {
+		return nil, err
+	}
+	known := map[string]bool{}
+	for _, r := range decided {
+		known[r.Pattern] = true
+	}
+	subscriptions := []recurring.Subscription{}
+	for _, s := range recurring.Detect(transactions, loc) {
+		if !known[s.Pattern] {
+			subscriptions = append(subscriptions, s)
+		}
+	}
+	return subscriptions, nil
+}
+
+// RecurringDetect lists the recurring charges found in the user's history
+// that they have not confirmed or dismissed yet.
+func (server *Server) RecurringDetect(c *gin.Context) {
+	loc, err := requestLocation(c)
+	if err != nil {
+		return
+	}
+	user := server.store.GetUserFromToken(c)
+	subscriptions, err := server.detectRecurring(user, loc)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	c.JSON(200, gin.H{
+		"subscriptions": subscriptions,
+	})
+}
+
+// RecurringIndex lists the confirmed and dismissed recurring charges, with
+// the next date of every confirmed one brought up to date.
+func (server *Server) RecurringIndex(c *gin.Context) {
+	loc, err := requestLocation(c)
+	if err != nil {
+		return
+	}
+	user := server.store.GetUserFromToken(c)
+	recurrings, err := server.store.GetRecurrings(user.UID)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	// charges due earlier today are still today's, in the user's time zone
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	for i := range recurrings {
+		recurrings[i].NextDate = recurring.Upcoming(recurrings[i].Cadence, recurring.Anchor(recurrings[i]).In(loc), today)
+	}
+	c.JSON(200, gin.H{
+		"recurring": recurrings,
+	})
+}
+
+// decideRecurring records the user's decision on the detected recurring
+// charge named by the Pattern in the body.
+func (server *Server) decideRecurring(c *gin.Context, status string) {
+	var body struct {
+		Pattern string `json:"Pattern" binding:"required"`
+	}
+	err := c.BindJSON(&body)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	loc, err := requestLocation(c)
+	if err != nil {
+		return
+	}
+	user := server.store.GetUserFromToken(c)
+	subscriptions, err := server.detectRecurring(user, loc)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	for _, s := range subscriptions {
+		if s.Pattern != body.Pattern {
+			continue
+		}
+		r := models.Recurring{
+			Pattern:    s.Pattern,
+			Title:      s.Title,
+			Status:     status,
+			Cadence:    s.Cadence,
+			Amount:     int(s.AverageAmount),
+			LastDate:   s.LastDate,
+			NextDate:   s.NextDate,
+			CategoryID: s.CategoryID,
+			UserID:     user.UID,
+		}
+		res, err := server.store.CreateRecurring(&r)
+		if err != nil {
+			c.Status(500)
+			return
+		}
+		c.JSON(200, gin.H{
+			"recurring": res,
+		})
+		return
+	}
+	c.JSON(400, gin.H{"error": "no undecided recurring charge with this pattern"})
+}
+
+// RecurringConfirm turns a detected charge into a recurring definition.
+func (server *Server) RecurringConfirm(c *gin.Context) {
+	server.decideRecurring(c, recurringConfirmed)
+}
+
+// RecurringDismiss stops a detected charge from being suggested again.
+func (server *Server) RecurringDismiss(c *gin.Context) {
+	server.decideRecurring(c, recurringDismissed)
+}
+
+func (server *Server) RecurringDelete(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	rId, uuidErr := uuid.Parse(c.Param("id"))
+	if uuidErr != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "invalid uuid"})
+		return
+	}
+	r := models.Recurring{ID: rId, UserID: user.UID}
+	res := server.store.DeleteRecurring(&r)
+	if res != nil {
+		c.Status(400)
+		return
+	}
+	c.JSON(200, gin.H{
+		"recurring": r,
+	})
+}
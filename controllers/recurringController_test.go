@@ -0,0 +1,411 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mock_store "github.com/peternabil/go-api/mocks"
+	"github.com/peternabil/go-api/models"
+	"github.com/peternabil/go-api/recurring"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func recurringHistory(user models.User, entertainment, health, food uuid.UUID) []models.Transaction {
+	transactions := []models.Transaction{}
+	add := func(title string, amount int, category uuid.UUID, date time.Time) {
+		transaction := models.Transaction{Title: title, Amount: amount, Negative: true, CategoryID: category, UserID: user.UID}
+		transaction.CreatedAt = date
+		transactions = append(transactions, transaction)
+	}
+	for i, amount := range []int{1599, 1599, 1599, 1799} {
+		add(fmt.Sprintf("NETFLIX.COM #%d", 100+i), amount, entertainment, time.Date(2023, time.Month(8+i), 14, 9, 0, 0, 0, time.UTC))
+	}
+	for i := 0; i < 5; i++ {
+		add("Gym", 1000, health, time.Date(2023, 11, 1+7*i, 18, 0, 0, 0, time.UTC))
+	}
+	for i, day := range []int{2, 3, 9, 20, 21} {
+		add("Groceries", 2000+300*i, food, time.Date(2023, 11, day, 12, 0, 0, 0, time.UTC))
+	}
+	return transactions
+}
+
+func TestRecurringDetect(t *testing.T) {
+	password := "Password123"
+	encryptedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
+	user := models.User{
+		UID:       uuid.New(),
+		Email:     "user@test.com",
+		FirstName: "test",
+		LastName:  "user",
+		Password:  string(encryptedPass),
+	}
+	entertainment := uuid.New()
+	health := uuid.New()
+	food := uuid.New()
+	history := recurringHistory(user, entertainment, health, food)
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mock_store.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "success",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetAllTransactions(user.UID).Times(1).Return(history, nil)
+				store.EXPECT().
+					GetRecurrings(user.UID).Times(1).Return([]models.Recurring{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					Subscriptions []recurring.Subscription `json:"subscriptions"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Len(t, res.Subscriptions, 2)
+				require.Equal(t, "gym", res.Subscriptions[0].Pattern)
+				require.Equal(t, recurring.Weekly, res.Subscriptions[0].Cadence)
+				require.Equal(t, int64(52000), res.Subscriptions[0].AnnualCost)
+				require.Equal(t, time.Date(2023, 12, 6, 18, 0, 0, 0, time.UTC), res.Subscriptions[0].NextDate.UTC())
+				require.Equal(t, "netflix com", res.Subscriptions[1].Pattern)
+				require.Equal(t, recurring.Monthly, res.Subscriptions[1].Cadence)
+				require.Equal(t, 4, res.Subscriptions[1].Charges)
+				require.Equal(t, int64(1649), res.Subscriptions[1].AverageAmount)
+				require.Equal(t, entertainment, res.Subscriptions[1].CategoryID)
+				require.Equal(t, time.Date(2023, 12, 14, 9, 0, 0, 0, time.UTC), res.Subscriptions[1].NextDate.UTC())
+			},
+		},
+		{
+			name: "decided charges are left out",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetAllTransactions(user.UID).Times(1).Return(history, nil)
+				store.EXPECT().
+					GetRecurrings(user.UID).Times(1).Return([]models.Recurring{{Pattern: "gym", Status: recurringDismissed}}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					Subscriptions []recurring.Subscription `json:"subscriptions"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Len(t, res.Subscriptions, 1)
+				require.Equal(t, "netflix com", res.Subscriptions[0].Pattern)
+			},
+		},
+		{
+			name: "db error",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetAllTransactions(user.UID).Times(1).Return(nil, errors.New("db error"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockStore := mock_store.NewMockStore(mockCtrl)
+			tt.buildStubs(mockStore)
+
+			server, _ := NewServer(mockStore, nil)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest("GET", "/smart-account/api/v1/recurring/detect", nil)
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(recorder)
+		})
+	}
+}
+
+func TestRecurringIndex(t *testing.T) {
+	password := "Password123"
+	encryptedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
+	user := models.User{
+		UID:       uuid.New(),
+		Email:     "user@test.com",
+		FirstName: "test",
+		LastName:  "user",
+		Password:  string(encryptedPass),
+	}
+	// monday evenings in UTC are tuesday mornings in Kiritimati
+	recurrings := func() []models.Recurring {
+		return []models.Recurring{{Pattern: "gym", Title: "Gym", Status: recurringConfirmed, Cadence: recurring.Weekly, Amount: 1000,
+			NextDate: time.Date(2023, 1, 2, 18, 0, 0, 0, time.UTC), UserID: user.UID}}
+	}
+	testCases := []struct {
+		name          string
+		param         string
+		buildStubs    func(store *mock_store.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "next dates brought up to date",
+			param: "?tz=Pacific/Kiritimati",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetRecurrings(user.UID).Times(1).Return(recurrings(), nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					Recurring []models.Recurring `json:"recurring"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Len(t, res.Recurring, 1)
+				loc, err := time.LoadLocation("Pacific/Kiritimati")
+				require.NoError(t, err)
+				now := time.Now().In(loc)
+				today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+				next := res.Recurring[0].NextDate.In(loc)
+				require.False(t, next.Before(today))
+				require.True(t, next.Before(today.AddDate(0, 0, 7)))
+				require.Equal(t, time.Tuesday, next.Weekday())
+				require.Equal(t, 8, next.Hour())
+			},
+		},
+		{
+			name:  "unknown time zone",
+			param: "?tz=Mars/Olympus",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "db error",
+			param: "",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetRecurrings(user.UID).Times(1).Return(nil, errors.New("db error"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockStore := mock_store.NewMockStore(mockCtrl)
+			tt.buildStubs(mockStore)
+
+			server, _ := NewServer(mockStore, nil)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest("GET", fmt.Sprintf("/smart-account/api/v1/recurring%s", tt.param), nil)
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(recorder)
+		})
+	}
+}
+
+func TestRecurringDecide(t *testing.T) {
+	password := "Password123"
+	encryptedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
+	user := models.User{
+		UID:       uuid.New(),
+		Email:     "user@test.com",
+		FirstName: "test",
+		LastName:  "user",
+		Password:  string(encryptedPass),
+	}
+	entertainment := uuid.New()
+	health := uuid.New()
+	food := uuid.New()
+	history := recurringHistory(user, entertainment, health, food)
+	testCases := []struct {
+		name          string
+		path          string
+		body          gin.H
+		buildStubs    func(store *mock_store.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "confirm",
+			path: "/smart-account/api/v1/recurring/confirm",
+			body: gin.H{"Pattern": "netflix com"},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetAllTransactions(user.UID).Times(1).Return(history, nil)
+				store.EXPECT().
+					GetRecurrings(user.UID).Times(1).Return([]models.Recurring{}, nil)
+				store.EXPECT().
+					CreateRecurring(gomock.Any()).Times(1).
+					DoAndReturn(func(r *models.Recurring) (models.Recurring, error) {
+						require.Equal(t, recurringConfirmed, r.Status)
+						require.Equal(t, recurring.Monthly, r.Cadence)
+						require.Equal(t, 1649, r.Amount)
+						require.Equal(t, entertainment, r.CategoryID)
+						require.Equal(t, user.UID, r.UserID)
+						return *r, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "dismiss",
+			path: "/smart-account/api/v1/recurring/dismiss",
+			body: gin.H{"Pattern": "gym"},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetAllTransactions(user.UID).Times(1).Return(history, nil)
+				store.EXPECT().
+					GetRecurrings(user.UID).Times(1).Return([]models.Recurring{}, nil)
+				store.EXPECT().
+					CreateRecurring(gomock.Any()).Times(1).
+					DoAndReturn(func(r *models.Recurring) (models.Recurring, error) {
+						require.Equal(t, recurringDismissed, r.Status)
+						require.Equal(t, "gym", r.Pattern)
+						return *r, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "not detected",
+			path: "/smart-account/api/v1/recurring/confirm",
+			body: gin.H{"Pattern": "groceries"},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetAllTransactions(user.UID).Times(1).Return(history, nil)
+				store.EXPECT().
+					GetRecurrings(user.UID).Times(1).Return([]models.Recurring{}, nil)
+				store.EXPECT().
+					CreateRecurring(gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "already decided",
+			path: "/smart-account/api/v1/recurring/confirm",
+			body: gin.H{"Pattern": "gym"},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetAllTransactions(user.UID).Times(1).Return(history, nil)
+				store.EXPECT().
+					GetRecurrings(user.UID).Times(1).Return([]models.Recurring{{Pattern: "gym", Status: recurringDismissed}}, nil)
+				store.EXPECT().
+					CreateRecurring(gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "missing pattern",
+			path: "/smart-account/api/v1/recurring/confirm",
+			body: gin.H{},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "server error",
+			path: "/smart-account/api/v1/recurring/confirm",
+			body: gin.H{"Pattern": "gym"},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetAllTransactions(user.UID).Times(1).Return(history, nil)
+				store.EXPECT().
+					GetRecurrings(user.UID).Times(1).Return([]models.Recurring{}, nil)
+				store.EXPECT().
+					CreateRecurring(gomock.Any()).Times(1).Return(models.Recurring{}, errors.New("db error"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockStore := mock_store.NewMockStore(mockCtrl)
+			tt.buildStubs(mockStore)
+
+			server, _ := NewServer(mockStore, nil)
+			recorder := httptest.NewRecorder()
+
+			body, err := json.Marshal(tt.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest("POST", tt.path, bytes.NewReader(body))
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/peternabil/go-api/models"
+	"github.com/peternabil/go-api/reports"
+)
+
+type reportBody struct {
+	Name       string `json:"Name" binding:"required,min=1"`
+	Dimensions string
+	Measures   string `json:"Measures" binding:"required"`
+	Category   string
+	Priority   string
+	Tag        string
+	Negative   *bool
+	MinAmount  *int
+	MaxAmount  *int
+}
+
+// bindReport validates the body and copies it onto report, checking that the
+// definition compiles and that any category or priority filtered on belongs
+// to the user.
+func (server *Server) bindReport(c *gin.Context, user models.User, report *models.Report) error {
+	var body reportBody
+	err := c.BindJSON(&body)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return err
+	}
+	categoryID, err := parseOptionalUUID(body.Category)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid category uuid"})
+		return err
+	}
+	if categoryID != nil {
+		cat := models.Category{ID: *categoryID}
+		if _, err = server.store.GetCategory(user.UID, &cat); err != nil {
+			c.JSON(400, gin.H{"error": "category not found"})
+			return err
+		}
+	}
+	priorityID, err := parseOptionalUUID(body.Priority)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid priority uuid"})
+		return err
+	}
+	if priorityID != nil {
+		prio := models.Priority{ID: *priorityID}
+		if _, err = server.store.GetPriority(user.UID, &prio); err != nil {
+			c.JSON(400, gin.H{"error": "priority not found"})
+			return err
+		}
+	}
+	report.Name = body.Name
+	report.Dimensions = body.Dimensions
+	report.Measures = body.Measures
+	report.CategoryID = categoryID
+	report.PriorityID = priorityID
+	report.Tag = body.Tag
+	report.Negative = body.Negative
+	report.MinAmount = body.MinAmount
+	report.MaxAmount = body.MaxAmount
+	report.UserID = user.UID
+	if err = reports.Validate(*report); err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return err
+	}
+	return nil
+}
+
+func (server *Server) ReportIndex(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	res, err := server.store.GetReports(user.UID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no reports for this user"})
+		return
+	}
+	c.JSON(200, gin.H{
+		"reports": res,
+	})
+}
+
+func (server *Server) ReportFind(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	rId, uuidErr := uuid.Parse(c.Param("id"))
+	if uuidErr != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "invalid uuid"})
+		return
+	}
+	report := models.Report{ID: rId}
+	res, err := server.store.GetReport(user.UID, &report)
+	if err != nil {
+		c.Status(404)
+		return
+	}
+	c.JSON(200, gin.H{
+		"report": res,
+	})
+}
+
+func (server *Server) ReportCreate(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	report := models.Report{}
+	if err := server.bindReport(c, user, &report); err != nil {
+		return
+	}
+	res, err := server.store.CreateReport(&report)
+	if err != nil {
+		c.Status(400)
+		return
+	}
+	c.JSON(200, gin.H{
+		"report": res,
+	})
+}
+
+func (server *Server) ReportEdit(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	rId, uuidErr := uuid.Parse(c.Param("id"))
+	if uuidErr != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "invalid uuid"})
+		return
+	}
+	report := models.Report{ID: rId}
+	res, err := server.store.GetReport(user.UID, &report)
+	if err != nil {
+		c.Status(404)
+		return
+	}
+	report = res
+	if err = server.bindReport(c, user, &report); err != nil {
+		return
+	}
+	res, err = server.store.EditReport(&report)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	c.JSON(200, gin.H{
+		"report": res,
+	})
+}
+
+func (server *Server) ReportDelete(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	rId, uuidErr := uuid.Parse(c.Param("id"))
+	if uuidErr != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "invalid uuid"})
+		return
+	}
+	report := models.Report{ID: rId, UserID: user.UID}
+	res := server.store.DeleteReport(&report)
+	if res != nil {
+		c.Status(400)
+		return
+	}
+	c.JSON(200, gin.H{
+		"report": report,
+	})
+}
+
+// ReportRun runs a saved report over the given dates or named range. Every
+// row holds the report's dimensions followed by its measures.
+func (server *Server) ReportRun(c *gin.Context) {
+	var startDate, endDate time.Time
+	err := setDates(c, &startDate, &endDate)
+	if err != nil {
+		return
+	}
+	user := server.store.GetUserFromToken(c)
+	rId, uuidErr := uuid.Parse(c.Param("id"))
+	if uuidErr != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "invalid uuid"})
+		return
+	}
+	report := models.Report{ID: rId}
+	report, err = server.store.GetReport(user.UID, &report)
+	if err != nil {
+		c.Status(404)
+		return
+	}
+	rows, err := server.store.RunReport(user.UID, report, startDate, endDate)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	c.JSON(200, gin.H{
+		"report":     report,
+		"start_date": startDate,
+		"end_date":   endDate,
+		"rows":       rows,
+	})
+}
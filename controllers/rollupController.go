@@ -0,0 +1,33 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// RollupCheck compares the user's daily rollups to their transactions and
+// lists every day, category, priority and sign where the two disagree.
+func (server *Server) RollupCheck(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	mismatches, err := server.store.CheckRollups(user.UID)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	c.JSON(200, gin.H{
+		"consistent": len(mismatches) == 0,
+		"mismatches": mismatches,
+	})
+}
+
+// RollupRebuild recomputes the user's daily rollups from their transactions.
+func (server *Server) RollupRebuild(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	err := server.store.RebuildRollups(user.UID)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	c.JSON(200, gin.H{
+		"rebuilt": true,
+	})
+}
@@ -0,0 +1,231 @@
+package controllers
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/peternabil/go-api/models"
+)
+
+type ruleBody struct {
+	Name               string `json:"Name" binding:"required,min=1"`
+	Position           int
+	TitlePattern       string
+	DescriptionPattern string
+	MinAmount          *int
+	MaxAmount          *int
+	Negative           *bool
+	Weekday            *time.Weekday `json:"Weekday" binding:"omitempty,min=0,max=6"`
+	SetCategory        string
+	SetPriority        string
+	SetTags            string
+	SetTitle           string
+	StopProcessing     bool
+}
+
+// bindRule validates the body and copies it onto rule, checking that any
+// referenced category or priority belongs to the user.
+func (server *Server) bindRule(c *gin.Context, user models.User, rule *models.Rule) error {
+	var body ruleBody
+	err := c.BindJSON(&body)
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return err
+	}
+	for _, pattern := range []string{body.TitlePattern, body.DescriptionPattern} {
+		if _, err = regexp.Compile(pattern); err != nil {
+			c.JSON(400, gin.H{"error": err.Error()})
+			return err
+		}
+	}
+	setCategoryID, err := parseOptionalUUID(body.SetCategory)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid category uuid"})
+		return err
+	}
+	if setCategoryID != nil {
+		cat := models.Category{ID: *setCategoryID}
+		if _, err = server.store.GetCategory(user.UID, &cat); err != nil {
+			c.JSON(400, gin.H{"error": "category not found"})
+			return err
+		}
+	}
+	setPriorityID, err := parseOptionalUUID(body.SetPriority)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "invalid priority uuid"})
+		return err
+	}
+	if setPriorityID != nil {
+		prio := models.Priority{ID: *setPriorityID}
+		if _, err = server.store.GetPriority(user.UID, &prio); err != nil {
+			c.JSON(400, gin.H{"error": "priority not found"})
+			return err
+		}
+	}
+	rule.Name = body.Name
+	rule.Position = body.Position
+	rule.TitlePattern = body.TitlePattern
+	rule.DescriptionPattern = body.DescriptionPattern
+	rule.MinAmount = body.MinAmount
+	rule.MaxAmount = body.MaxAmount
+	rule.Negative = body.Negative
+	rule.Weekday = body.Weekday
+	rule.SetCategoryID = setCategoryID
+	rule.SetPriorityID = setPriorityID
+	rule.SetTags = body.SetTags
+	rule.SetTitle = body.SetTitle
+	rule.StopProcessing = body.StopProcessing
+	rule.UserID = user.UID
+	return nil
+}
+
+func (server *Server) RuleIndex(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	rules, err := server.store.GetRules(user.UID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no rules for this user"})
+		return
+	}
+	c.JSON(200, gin.H{
+		"rules": rules,
+	})
+}
+
+func (server *Server) RuleFind(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	rId, uuidErr := uuid.Parse(c.Param("id"))
+	if uuidErr != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "invalid uuid"})
+		return
+	}
+	rule := models.Rule{ID: rId}
+	res, err := server.store.GetRule(user.UID, &rule)
+	if err != nil {
+		c.Status(404)
+		return
+	}
+	c.JSON(200, gin.H{
+		"rule": res,
+	})
+}
+
+func (server *Server) RuleCreate(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	rule := models.Rule{}
+	if err := server.bindRule(c, user, &rule); err != nil {
+		return
+	}
+	res, err := server.store.CreateRule(&rule)
+	if err != nil {
+		c.Status(400)
+		return
+	}
+	c.JSON(200, gin.H{
+		"rule": res,
+	})
+}
+
+func (server *Server) RuleEdit(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	rId, uuidErr := uuid.Parse(c.Param("id"))
+	if uuidErr != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "invalid uuid"})
+		return
+	}
+	rule := models.Rule{ID: rId}
+	res, err := server.store.GetRule(user.UID, &rule)
+	if err != nil {
+		c.Status(404)
+		return
+	}
+	rule = res
+	if err = server.bindRule(c, user, &rule); err != nil {
+		return
+	}
+	res, err = server.store.EditRule(&rule)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	c.JSON(200, gin.H{
+		"rule": res,
+	})
+}
+
+func (server *Server) RuleDelete(c *gin.Context) {
+	user := server.store.GetUserFromToken(c)
+	rId, uuidErr := uuid.Parse(c.Param("id"))
+	if uuidErr != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "invalid uuid"})
+		return
+	}
+	rule := models.Rule{ID: rId, UserID: user.UID}
+	res := server.store.DeleteRule(&rule)
+	if res != nil {
+		c.Status(400)
+		return
+	}
+	c.JSON(200, gin.H{
+		"rule": rule,
+	})
+}
+
+// RuleApply re-runs the user's rules over all of their transactions. With
+// dry_run (the default) it only reports what would change.
+func (server *Server) RuleApply(c *gin.Context) {
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "true"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	loc, err := requestLocation(c)
+	if err != nil {
+		return
+	}
+	user := server.store.GetUserFromToken(c)
+	rules, err := server.store.GetRules(user.UID)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	compiled := compileRules(rules)
+	transactions, err := server.store.GetAllTransactions(user.UID)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	type transactionDiff struct {
+		TransactionID uuid.UUID
+		Title         string
+		Changes       map[string]ruleChange
+	}
+	diffs := []transactionDiff{}
+	changed := []models.Transaction{}
+	for _, transaction := range transactions {
+		title := transaction.Title
+		changes := applyRules(compiled, &transaction, loc)
+		if len(changes) == 0 {
+			continue
+		}
+		diffs = append(diffs, transactionDiff{TransactionID: transaction.ID, Title: title, Changes: changes})
+		changed = append(changed, transaction)
+	}
+	if !dryRun && len(changed) > 0 {
+		err = server.store.EditTransactions(changed)
+		if err != nil {
+			c.Status(500)
+			return
+		}
+		for _, transaction := range changed {
+			server.classifiers.Learn(transaction)
+		}
+	}
+	c.JSON(200, gin.H{
+		"dry_run": dryRun,
+		"changes": diffs,
+	})
+}
@@ -0,0 +1,417 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mock_store "github.com/peternabil/go-api/mocks"
+	"github.com/peternabil/go-api/models"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestListRules(t *testing.T) {
+	password := "Password123"
+	encryptedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
+	user := models.User{
+		UID:       uuid.New(),
+		Email:     "user@test.com",
+		FirstName: "test",
+		LastName:  "user",
+		Password:  string(encryptedPass),
+	}
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mock_store.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "success",
+			buildStubs: func(store *mock_store.MockStore) {
+				rules := []models.Rule{{
+					ID:           uuid.New(),
+					Name:         "Rule A",
+					TitlePattern: "^UBER",
+					UserID:       user.UID,
+				}}
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetRules(gomock.Any()).Times(1).Return(rules, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "error in rules",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetRules(gomock.Any()).Times(1).Return(nil, errors.New("error in rules"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockStore := mock_store.NewMockStore(mockCtrl)
+			tt.buildStubs(mockStore)
+
+			server, _ := NewServer(mockStore, nil)
+			recorder := httptest.NewRecorder()
+
+			reader := strings.NewReader("")
+
+			request, err := http.NewRequest("GET", "/smart-account/api/v1/rule", reader)
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(recorder)
+		})
+	}
+}
+
+func TestCreateRule(t *testing.T) {
+	password := "Password123"
+	encryptedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
+	user := models.User{
+		UID:       uuid.New(),
+		Email:     "user@test.com",
+		FirstName: "test",
+		LastName:  "user",
+		Password:  string(encryptedPass),
+	}
+	category := models.Category{
+		ID:     uuid.New(),
+		Name:   "Category A",
+		UserID: user.UID,
+	}
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mock_store.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "success",
+			body: gin.H{
+				"Name":         "rides",
+				"TitlePattern": "^UBER",
+				"MinAmount":    10,
+				"Weekday":      5,
+				"SetCategory":  category.ID.String(),
+			},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetCategory(gomock.Any(), gomock.Any()).Times(1).Return(category, nil)
+				store.EXPECT().
+					CreateRule(gomock.Any()).Times(1).DoAndReturn(func(rule *models.Rule) (models.Rule, error) {
+					require.Equal(t, category.ID, *rule.SetCategoryID)
+					require.Equal(t, 10, *rule.MinAmount)
+					require.Nil(t, rule.MaxAmount)
+					return *rule, nil
+				})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "invalid pattern",
+			body: gin.H{
+				"Name":         "rides",
+				"TitlePattern": "(",
+			},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "invalid weekday",
+			body: gin.H{
+				"Name":    "rides",
+				"Weekday": 7,
+			},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "category not found",
+			body: gin.H{
+				"Name":        "rides",
+				"SetCategory": category.ID.String(),
+			},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetCategory(gomock.Any(), gomock.Any()).Times(1).Return(models.Category{}, errors.New("not found"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockStore := mock_store.NewMockStore(mockCtrl)
+			tt.buildStubs(mockStore)
+
+			server, _ := NewServer(mockStore, nil)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tt.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest("POST", "/smart-account/api/v1/rule", bytes.NewReader(data))
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(recorder)
+		})
+	}
+}
+
+func TestApplyRules(t *testing.T) {
+	password := "Password123"
+	encryptedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
+	user := models.User{
+		UID:       uuid.New(),
+		Email:     "user@test.com",
+		FirstName: "test",
+		LastName:  "user",
+		Password:  string(encryptedPass),
+	}
+	categoryID := uuid.New()
+	negative := true
+	rules := []models.Rule{{
+		ID:            uuid.New(),
+		Name:          "rides",
+		TitlePattern:  "(?i)uber",
+		Negative:      &negative,
+		SetCategoryID: &categoryID,
+		UserID:        user.UID,
+	}}
+	transactions := []models.Transaction{
+		{ID: uuid.New(), Title: "Uber trip", Amount: 20, Negative: true, CategoryID: uuid.New(), UserID: user.UID},
+		{ID: uuid.New(), Title: "Salary", Amount: 1000, Negative: false, CategoryID: uuid.New(), UserID: user.UID},
+	}
+	saturday := time.Saturday
+	weekendRules := []models.Rule{{
+		ID:            uuid.New(),
+		Name:          "weekend",
+		Weekday:       &saturday,
+		SetCategoryID: &categoryID,
+		UserID:        user.UID,
+	}}
+	// a friday evening in UTC is already saturday in Tokyo
+	fridayEvening := models.Transaction{ID: uuid.New(), Title: "Dinner", Amount: 40, Negative: true, CategoryID: uuid.New(), UserID: user.UID}
+	fridayEvening.CreatedAt = time.Date(2023, 12, 15, 20, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name          string
+		param         string
+		buildStubs    func(store *mock_store.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "dry run",
+			param: "",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetRules(gomock.Any()).Times(1).Return(rules, nil)
+				store.EXPECT().
+					GetAllTransactions(gomock.Any()).Times(1).Return(transactions, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					Changes []struct {
+						TransactionID uuid.UUID
+						Changes       map[string]ruleChange
+					} `json:"changes"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Len(t, res.Changes, 1)
+				require.Equal(t, transactions[0].ID, res.Changes[0].TransactionID)
+				require.Equal(t, categoryID.String(), res.Changes[0].Changes["Category"].To)
+			},
+		},
+		{
+			name:  "apply",
+			param: "?dry_run=false",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetRules(gomock.Any()).Times(1).Return(rules, nil)
+				store.EXPECT().
+					GetAllTransactions(gomock.Any()).Times(1).Return(transactions, nil)
+				store.EXPECT().
+					EditTransactions(gomock.Any()).Times(1).DoAndReturn(func(changed []models.Transaction) error {
+					require.Len(t, changed, 1)
+					require.Equal(t, categoryID, changed[0].CategoryID)
+					return nil
+				})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "weekday in the user's time zone",
+			param: "?tz=Asia/Tokyo",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetRules(gomock.Any()).Times(1).Return(weekendRules, nil)
+				store.EXPECT().
+					GetAllTransactions(gomock.Any()).Times(1).Return([]models.Transaction{fridayEvening}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					Changes []struct {
+						TransactionID uuid.UUID
+					} `json:"changes"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Len(t, res.Changes, 1)
+				require.Equal(t, fridayEvening.ID, res.Changes[0].TransactionID)
+			},
+		},
+		{
+			name:  "weekday in UTC",
+			param: "",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetRules(gomock.Any()).Times(1).Return(weekendRules, nil)
+				store.EXPECT().
+					GetAllTransactions(gomock.Any()).Times(1).Return([]models.Transaction{fridayEvening}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					Changes []struct {
+						TransactionID uuid.UUID
+					} `json:"changes"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Empty(t, res.Changes)
+			},
+		},
+		{
+			name:  "unknown time zone",
+			param: "?tz=Mars/Olympus",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "wrong dry run param",
+			param: "?dry_run=test",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "db error",
+			param: "?dry_run=false",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetRules(gomock.Any()).Times(1).Return(rules, nil)
+				store.EXPECT().
+					GetAllTransactions(gomock.Any()).Times(1).Return(transactions, nil)
+				store.EXPECT().
+					EditTransactions(gomock.Any()).Times(1).Return(errors.New("db error"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockStore := mock_store.NewMockStore(mockCtrl)
+			tt.buildStubs(mockStore)
+
+			server, _ := NewServer(mockStore, nil)
+			recorder := httptest.NewRecorder()
+
+			reader := strings.NewReader("")
+
+			request, err := http.NewRequest("POST", fmt.Sprintf("/smart-account/api/v1/rule/apply%s", tt.param), reader)
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/peternabil/go-api/models"
+)
+
+type ruleChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// compiledRule is a rule with its patterns compiled, so that running it over
+// many transactions compiles them only once. A rule whose pattern does not
+// compile never matches.
+type compiledRule struct {
+	models.Rule
+	title       *regexp.Regexp
+	description *regexp.Regexp
+	invalid     bool
+}
+
+// compileRules compiles the patterns of the rules, keeping their order.
+func compileRules(rules []models.Rule) []compiledRule {
+	compiled := make([]compiledRule, len(rules))
+	for i, rule := range rules {
+		compiled[i].Rule = rule
+		var err error
+		if rule.TitlePattern != "" {
+			if compiled[i].title, err = regexp.Compile(rule.TitlePattern); err != nil {
+				compiled[i].invalid = true
+			}
+		}
+		if rule.DescriptionPattern != "" {
+			if compiled[i].description, err = regexp.Compile(rule.DescriptionPattern); err != nil {
+				compiled[i].invalid = true
+			}
+		}
+	}
+	return compiled
+}
+
+// ruleMatches reports whether every condition set on the rule holds for the
+// transaction, taking its weekday in loc.
+func ruleMatches(rule compiledRule, transaction models.Transaction, loc *time.Location) bool {
+	if rule.invalid {
+		return false
+	}
+	if rule.title != nil && !rule.title.MatchString(transaction.Title) {
+		return false
+	}
+	if rule.description != nil && !rule.description.MatchString(transaction.Description) {
+		return false
+	}
+	if rule.MinAmount != nil && transaction.Amount < *rule.MinAmount {
+		return false
+	}
+	if rule.MaxAmount != nil && transaction.Amount > *rule.MaxAmount {
+		return false
+	}
+	if rule.Negative != nil && transaction.Negative != *rule.Negative {
+		return false
+	}
+	if rule.Weekday != nil {
+		date := transaction.CreatedAt
+		if date.IsZero() {
+			date = time.Now()
+		}
+		if date.In(loc).Weekday() != *rule.Weekday {
+			return false
+		}
+	}
+	return true
+}
+
+// applyRules runs the rules in order against the transaction and returns the fields that changed.
+func applyRules(rules []compiledRule, transaction *models.Transaction, loc *time.Location) map[string]ruleChange {
+	changes := map[string]ruleChange{}
+	record := func(field string, from, to any) {
+		if change, ok := changes[field]; ok {
+			from = change.From
+		}
+		if from == to {
+			delete(changes, field)
+			return
+		}
+		changes[field] = ruleChange{From: from, To: to}
+	}
+	for _, rule := range rules {
+		if !ruleMatches(rule, *transaction, loc) {
+			continue
+		}
+		if rule.SetCategoryID != nil && *rule.SetCategoryID != transaction.CategoryID {
+			record("Category", transaction.CategoryID, *rule.SetCategoryID)
+			transaction.CategoryID = *rule.SetCategoryID
+		}
+		if rule.SetPriorityID != nil && *rule.SetPriorityID != transaction.PriorityID {
+			record("Priority", transaction.PriorityID, *rule.SetPriorityID)
+			transaction.PriorityID = *rule.SetPriorityID
+		}
+		if rule.SetTags != "" && rule.SetTags != transaction.Tags {
+			record("Tags", transaction.Tags, rule.SetTags)
+			transaction.Tags = rule.SetTags
+		}
+		if rule.SetTitle != "" && rule.SetTitle != transaction.Title {
+			record("Title", transaction.Title, rule.SetTitle)
+			transaction.Title = rule.SetTitle
+		}
+		if rule.StopProcessing {
+			break
+		}
+	}
+	return changes
+}
+
+// parseOptionalUUID parses s, treating an empty string as no value.
+func parseOptionalUUID(s string) (*uuid.UUID, error) {
+	if s == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
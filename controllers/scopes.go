@@ -23,15 +23,39 @@ func getPaginationArgs(r *http.Request) (int, int) {
 	return page, pageSize
 }
 
-func getDates(r *http.Request) (time.Time, time.Time, error, error) {
+// dateLayouts are the formats accepted for dates in query parameters. Times
+// without an offset are read in the request's time zone.
+var dateLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05"}
+
+// parseDate reads a date in any of the dateLayouts and returns it in loc.
+func parseDate(value string, loc *time.Location) (time.Time, error) {
+	var firstErr error
+	for _, layout := range dateLayouts {
+		date, err := time.ParseInLocation(layout, value, loc)
+		if err == nil {
+			return date.In(loc), nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return time.Time{}, firstErr
+}
+
+// inLocation returns the same wall clock time as t in loc.
+func inLocation(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
+
+func getDates(r *http.Request, loc *time.Location) (time.Time, time.Time, error, error) {
 	q := r.URL.Query()
 	startDatestr := q.Get("start_date")
-	startDate, sError := time.Parse("2006-01-02T15:04:05Z", startDatestr)
+	startDate, sError := parseDate(startDatestr, loc)
 	if sError != nil {
 		return time.Now(), time.Now(), sError, nil
 	}
 	endDatestr := q.Get("end_date")
-	endDate, sError := time.Parse("2006-01-02T15:04:05Z", endDatestr)
+	endDate, sError := parseDate(endDatestr, loc)
 	if sError != nil {
 		return time.Now(), time.Now(), nil, sError
 	}
@@ -7,20 +7,23 @@ import (
 	ginzap "github.com/gin-contrib/zap"
 	"github.com/gin-gonic/gin"
 	"github.com/penglongli/gin-metrics/ginmetrics"
+	"github.com/peternabil/go-api/classifier"
 	"github.com/peternabil/go-api/store"
 	"go.uber.org/zap"
 )
 
 type Server struct {
-	store  store.Store
-	router *gin.Engine
-	mw     gin.HandlerFunc // Optional middleware for testing
+	store       store.Store
+	router      *gin.Engine
+	mw          gin.HandlerFunc // Optional middleware for testing
+	classifiers *classifier.Cache
 }
 
 func NewServer(store store.Store, mw gin.HandlerFunc) (*Server, error) {
 	server := &Server{
-		store: store,
-		mw:    mw,
+		store:       store,
+		mw:          mw,
+		classifiers: classifier.NewCache(),
 	}
 	server.NewRouter()
 	return server, nil
@@ -38,6 +41,7 @@ func (server *Server) NewRouter() {
 	nonAuth := r.Group("/smart-account/api")
 	nonAuth.POST("/auth/signup", server.SignUp)
 	nonAuth.POST("/auth/login", server.Login)
+	nonAuth.GET("/template", server.TemplateIndex)
 	// auth required
 	auth := nonAuth.Group("/v1")
 	if server.mw != nil {
@@ -48,8 +52,11 @@ func (server *Server) NewRouter() {
 
 	auth.GET("/users", server.UserIndex)
 	auth.GET("/users/:id", server.UserFind)
+	auth.PUT("/users/timezone", server.UserTimeZone)
+	auth.PUT("/users/fiscal-month-start", server.UserFiscalMonthStart)
 
 	auth.GET("/transaction", server.TransactionIndex)
+	auth.GET("/transaction/suggest", server.TransactionSuggest)
 	auth.GET("/transaction/:id", server.TransactionFind)
 	auth.POST("/transaction", server.TransactionCreate)
 	auth.PUT("/transaction/:id", server.TransactionEdit)
@@ -60,16 +67,81 @@ func (server *Server) NewRouter() {
 	auth.POST("/category", server.CategoryCreate)
 	auth.PUT("/category/:id", server.CategoryEdit)
 	auth.DELETE("/category/:id", server.CategoryDelete)
+	auth.POST("/category/:id/merge", server.CategoryMerge)
 
 	auth.GET("/priority", server.PriorityIndex)
 	auth.GET("/priority/:id", server.PriorityFind)
 	auth.POST("/priority", server.PriorityCreate)
 	auth.PUT("/priority/:id", server.PriorityEdit)
 	auth.DELETE("/priority/:id", server.PriorityDelete)
+	auth.POST("/priority/:id/merge", server.PriorityMerge)
+
+	auth.POST("/template/:key/apply", server.TemplateApply)
+
+	auth.GET("/budget", server.BudgetIndex)
+	auth.GET("/budget/progress", server.BudgetProgress)
+	auth.GET("/budget/:id", server.BudgetFind)
+	auth.POST("/budget", server.BudgetCreate)
+	auth.PUT("/budget/:id", server.BudgetEdit)
+	auth.DELETE("/budget/:id", server.BudgetDelete)
+
+	auth.GET("/goal", server.GoalIndex)
+	auth.GET("/goal/progress", server.GoalIndexProgress)
+	auth.GET("/goal/:id", server.GoalFind)
+	auth.GET("/goal/:id/progress", server.GoalProgress)
+	auth.POST("/goal", server.GoalCreate)
+	auth.PUT("/goal/:id", server.GoalEdit)
+	auth.DELETE("/goal/:id", server.GoalDelete)
+
+	auth.GET("/debt", server.DebtIndex)
+	auth.GET("/debt/plan", server.DebtPlan)
+	auth.GET("/debt/:id", server.DebtFind)
+	auth.POST("/debt", server.DebtCreate)
+	auth.PUT("/debt/:id", server.DebtEdit)
+	auth.DELETE("/debt/:id", server.DebtDelete)
+
+	auth.GET("/recurring", server.RecurringIndex)
+	auth.GET("/recurring/detect", server.RecurringDetect)
+	auth.POST("/recurring/confirm", server.RecurringConfirm)
+	auth.POST("/recurring/dismiss", server.RecurringDismiss)
+	auth.DELETE("/recurring/:id", server.RecurringDelete)
+
+	auth.GET("/envelope", server.EnvelopeIndex)
+	auth.GET("/envelope/events", server.EnvelopeEvents)
+	auth.POST("/envelope/assign", server.EnvelopeAssign)
+	auth.POST("/envelope/move", server.EnvelopeMove)
+
+	auth.GET("/rule", server.RuleIndex)
+	auth.GET("/rule/:id", server.RuleFind)
+	auth.POST("/rule", server.RuleCreate)
+	auth.POST("/rule/apply", server.RuleApply)
+	auth.PUT("/rule/:id", server.RuleEdit)
+	auth.DELETE("/rule/:id", server.RuleDelete)
+
+	auth.GET("/report", server.ReportIndex)
+	auth.GET("/report/:id", server.ReportFind)
+	auth.GET("/report/:id/run", server.ReportRun)
+	auth.POST("/report", server.ReportCreate)
+	auth.PUT("/report/:id", server.ReportEdit)
+	auth.DELETE("/report/:id", server.ReportDelete)
 
 	auth.GET("/daily", server.GetDailyValues)
 	auth.GET("/highest-cat", server.GetHighestCategory)
 	auth.GET("/highest-prio", server.GetHighestPriority)
+	auth.GET("/cash-flow", server.GetCashFlow)
+	auth.GET("/summary", server.GetSummary)
+	auth.GET("/compare", server.GetComparison)
+	auth.GET("/pivot", server.GetPivot)
+	auth.GET("/stats", server.GetStats)
+	auth.GET("/patterns", server.GetPatterns)
+	auth.GET("/financial-health", server.GetHealth)
+	auth.GET("/forecast", server.GetForecast)
+	auth.GET("/anomalies", server.GetAnomalies)
+
+	auth.GET("/rollup/check", server.RollupCheck)
+	auth.POST("/rollup/rebuild", server.RollupRebuild)
+
+	auth.GET("/statement", server.GetStatement)
 
 	server.router = r
 }
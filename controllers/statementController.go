@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/peternabil/go-api/reports"
+)
+
+func (server *Server) GetStatement(c *gin.Context) {
+	var startDate time.Time
+	var endDate time.Time
+	err := setDates(c, &startDate, &endDate)
+	if err != nil {
+		return
+	}
+	user := server.store.GetUserFromToken(c)
+	statement := reports.Statement{User: user, StartDate: startDate, EndDate: endDate}
+	statement.OpeningBalance, err = server.store.GetBalance(user.UID, startDate)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	statement.Transactions, err = server.store.GetTransactionsDateRange(user.UID, startDate, endDate)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	statement.ExpenseCategories, err = server.store.GetHighestSpendingCategory(user.UID, startDate, endDate, true, 0)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	statement.IncomeCategories, err = server.store.GetHighestSpendingCategory(user.UID, startDate, endDate, false, 0)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	statement.ExpensePriorities, err = server.store.GetHighestSpendingPriority(user.UID, startDate, endDate, true)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	statement.IncomePriorities, err = server.store.GetHighestSpendingPriority(user.UID, startDate, endDate, false)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	var buf bytes.Buffer
+	err = statement.WritePDF(&buf)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "could not render statement"})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=statement-%s-%s.pdf", startDate.Format("2006-01-02"), endDate.Format("2006-01-02")))
+	c.Data(200, "application/pdf", buf.Bytes())
+}
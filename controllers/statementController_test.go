@@ -0,0 +1,154 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mock_store "github.com/peternabil/go-api/mocks"
+	"github.com/peternabil/go-api/models"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestGetStatement(t *testing.T) {
+	password := "Password123"
+	encryptedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
+	user := models.User{
+		UID:       uuid.New(),
+		Email:     "user@test.com",
+		FirstName: "test",
+		LastName:  "user",
+		Password:  string(encryptedPass),
+	}
+	category := models.Category{
+		ID:     uuid.New(),
+		Name:   "Category A",
+		UserID: user.UID,
+	}
+	priority := models.Priority{
+		ID:     uuid.New(),
+		Name:   "Priority A",
+		Level:  5,
+		UserID: user.UID,
+	}
+	transactions := []models.Transaction{{
+		ID:         uuid.New(),
+		Title:      "transaction title",
+		CategoryID: category.ID,
+		Category:   category,
+		Priority:   priority,
+		Amount:     100,
+		Negative:   true,
+		PriorityID: priority.ID,
+		UserID:     user.UID,
+	}}
+	categorySpendings := []models.SpendingCategory{{
+		Total:      100,
+		Cname:      category.Name,
+		CategoryID: category.ID,
+	}}
+	prioritySpendings := []models.SpendingPriority{{
+		Total:      100,
+		Pname:      priority.Name,
+		PriorityID: priority.ID,
+		Level:      priority.Level,
+	}}
+	testCases := []struct {
+		name          string
+		param         string
+		buildStubs    func(store *mock_store.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "success",
+			param: "?end_date=2023-12-19T16:23:25.742Z&start_date=2023-11-19T16:21:53.561Z",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetBalance(gomock.Any(), gomock.Any()).Times(1).Return(int64(500), nil)
+				store.EXPECT().
+					GetTransactionsDateRange(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(transactions, nil)
+				store.EXPECT().
+					GetHighestSpendingCategory(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(2).Return(categorySpendings, nil)
+				store.EXPECT().
+					GetHighestSpendingPriority(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(2).Return(prioritySpendings, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "application/pdf", recorder.Header().Get("Content-Type"))
+				require.True(t, strings.HasPrefix(recorder.Body.String(), "%PDF-"))
+			},
+		},
+		{
+			name:  "wrong end date format",
+			param: "?end_date=2023-12-19 16:23-25&start_date=2023-11-19T16:21:53.561Z",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "balance db error",
+			param: "?end_date=2023-12-19T16:23:25.742Z&start_date=2023-11-19T16:21:53.561Z",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetBalance(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), errors.New("db error"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:  "transactions db error",
+			param: "?end_date=2023-12-19T16:23:25.742Z&start_date=2023-11-19T16:21:53.561Z",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetBalance(gomock.Any(), gomock.Any()).Times(1).Return(int64(500), nil)
+				store.EXPECT().
+					GetTransactionsDateRange(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil, errors.New("db error"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockStore := mock_store.NewMockStore(mockCtrl)
+			tt.buildStubs(mockStore)
+
+			server, _ := NewServer(mockStore, nil)
+			recorder := httptest.NewRecorder()
+
+			reader := strings.NewReader("")
+
+			request, err := http.NewRequest("GET", fmt.Sprintf("/smart-account/api/v1/statement%s", tt.param), reader)
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(recorder)
+		})
+	}
+}
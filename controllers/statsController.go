@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetStats describes the transaction amounts of every category and priority
+// in the period: count, total, mean, median, 90th percentile, minimum and
+// maximum. The median is the typical amount of a transaction.
+func (server *Server) GetStats(c *gin.Context) {
+	var startDate, endDate time.Time
+	var negative bool
+	err := setDates(c, &startDate, &endDate)
+	if err != nil {
+		return
+	}
+	err = setNegative(c, &negative)
+	if err != nil {
+		return
+	}
+	user := server.store.GetUserFromToken(c)
+	categories, err := server.store.GetSpendingStats(user.UID, startDate, endDate, negative, rowsCategory)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	priorities, err := server.store.GetSpendingStats(user.UID, startDate, endDate, negative, rowsPriority)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	c.JSON(200, gin.H{
+		"start_date": startDate,
+		"end_date":   endDate,
+		"categories": categories,
+		"priorities": priorities,
+	})
+}
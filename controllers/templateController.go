@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/peternabil/go-api/models"
+	"github.com/peternabil/go-api/templates"
+)
+
+func (server *Server) TemplateIndex(c *gin.Context) {
+	locale := templates.Locale(c.Query("locale"), c.GetHeader("Accept-Language"))
+	type templateSummary struct {
+		Key        string
+		Name       string
+		Categories []models.Category
+		Priorities []models.Priority
+	}
+	summaries := []templateSummary{}
+	for _, template := range templates.All() {
+		categories, priorities := template.Build(uuid.Nil, locale)
+		summaries = append(summaries, templateSummary{Key: template.Key, Name: template.Name.Get(locale), Categories: categories, Priorities: priorities})
+	}
+	c.JSON(200, gin.H{
+		"locale":    locale,
+		"templates": summaries,
+	})
+}
+
+// TemplateApply seeds the template's categories and priorities for the user,
+// skipping any whose name the user already has.
+func (server *Server) TemplateApply(c *gin.Context) {
+	template, ok := templates.Find(c.Param("key"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "unknown template"})
+		return
+	}
+	user := server.store.GetUserFromToken(c)
+	locale := templates.Locale(c.Query("locale"), c.GetHeader("Accept-Language"))
+	existingCategories, err := server.store.GetCategories(user.UID)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	existingPriorities, err := server.store.GetPriorities(user.UID)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	names := map[string]bool{}
+	for _, cat := range existingCategories {
+		names["c:"+strings.ToLower(cat.Name)] = true
+	}
+	for _, prio := range existingPriorities {
+		names["p:"+strings.ToLower(prio.Name)] = true
+	}
+	newCategories, newPriorities := template.Build(user.UID, locale)
+	categories := []models.Category{}
+	for _, cat := range newCategories {
+		if !names["c:"+strings.ToLower(cat.Name)] {
+			categories = append(categories, cat)
+		}
+	}
+	priorities := []models.Priority{}
+	for _, prio := range newPriorities {
+		if !names["p:"+strings.ToLower(prio.Name)] {
+			priorities = append(priorities, prio)
+		}
+	}
+	err = server.store.SeedCategoriesAndPriorities(categories, priorities)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "could not apply template"})
+		return
+	}
+	c.JSON(200, gin.H{
+		"categories": categories,
+		"priorities": priorities,
+	})
+}
@@ -0,0 +1,174 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	mock_store "github.com/peternabil/go-api/mocks"
+	"github.com/peternabil/go-api/models"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestListTemplates(t *testing.T) {
+	testCases := []struct {
+		name          string
+		param         string
+		header        string
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "default locale",
+			param: "",
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					Locale    string `json:"locale"`
+					Templates []struct {
+						Key        string
+						Name       string
+						Priorities []models.Priority
+					} `json:"templates"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, "en", res.Locale)
+				require.Len(t, res.Templates, 3)
+				require.Equal(t, "freelancer", res.Templates[0].Key)
+				require.Equal(t, 10, res.Templates[0].Priorities[0].Level)
+			},
+		},
+		{
+			name:   "locale from header",
+			header: "ar-EG,ar;q=0.9,en;q=0.8",
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"locale":"ar"`)
+				require.Contains(t, recorder.Body.String(), "طالب")
+			},
+		},
+		{
+			name:  "unsupported locale",
+			param: "?locale=xx",
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"locale":"en"`)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockStore := mock_store.NewMockStore(mockCtrl)
+
+			server, _ := NewServer(mockStore, nil)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest("GET", fmt.Sprintf("/smart-account/api/template%s", tt.param), nil)
+			require.NoError(t, err)
+			request.Header.Set("Accept-Language", tt.header)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(recorder)
+		})
+	}
+}
+
+func TestApplyTemplate(t *testing.T) {
+	password := "Password123"
+	encryptedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
+	user := models.User{
+		UID:       uuid.New(),
+		Email:     "user@test.com",
+		FirstName: "test",
+		LastName:  "user",
+		Password:  string(encryptedPass),
+	}
+	testCases := []struct {
+		name          string
+		key           string
+		buildStubs    func(store *mock_store.MockStore)
+		checkResponse func(recoder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "success skips existing names",
+			key:  "household",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetCategories(user.UID).Times(1).Return([]models.Category{{ID: uuid.New(), Name: "groceries", UserID: user.UID}}, nil)
+				store.EXPECT().
+					GetPriorities(user.UID).Times(1).Return([]models.Priority{}, nil)
+				store.EXPECT().
+					SeedCategoriesAndPriorities(gomock.Any(), gomock.Any()).Times(1).DoAndReturn(func(categories []models.Category, priorities []models.Priority) error {
+					require.Len(t, categories, 6)
+					require.Len(t, priorities, 4)
+					for _, cat := range categories {
+						require.NotEqual(t, "Groceries", cat.Name)
+						require.Equal(t, user.UID, cat.UserID)
+					}
+					return nil
+				})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "unknown template",
+			key:  "pirate",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "db error",
+			key:  "student",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetCategories(user.UID).Times(1).Return([]models.Category{}, nil)
+				store.EXPECT().
+					GetPriorities(user.UID).Times(1).Return([]models.Priority{}, nil)
+				store.EXPECT().
+					SeedCategoriesAndPriorities(gomock.Any(), gomock.Any()).Times(1).Return(errors.New("db error"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockStore := mock_store.NewMockStore(mockCtrl)
+			tt.buildStubs(mockStore)
+
+			server, _ := NewServer(mockStore, nil)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest("POST", fmt.Sprintf("/smart-account/api/v1/template/%s/apply", tt.key), nil)
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(recorder)
+		})
+	}
+}
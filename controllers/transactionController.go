@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -52,35 +53,78 @@ func (server *Server) TransactionCreate(c *gin.Context) {
 		Negative    bool
 		Description string
 		Priority    string
+		Tags        string
+		Goal        string
+		Debt        string
 	}
 	err := c.BindJSON(&body)
 	if err != nil {
 		c.JSON(400, gin.H{"error": err.Error()})
 		return
 	}
+	loc, err := requestLocation(c)
+	if err != nil {
+		return
+	}
 	user := server.store.GetUserFromToken(c)
-	cat := models.Category{ID: uuid.MustParse(body.Category)}
-	prio := models.Priority{ID: uuid.MustParse(body.Priority)}
-	category, err := server.store.GetCategory(user.UID, &cat)
+	goalID, err := server.checkGoal(c, user, body.Goal)
+	if err != nil {
+		return
+	}
+	debtID, err := server.checkDebt(c, user, body.Debt)
 	if err != nil {
+		return
+	}
+	transaction := models.Transaction{Title: body.Title, Amount: body.Amount, Negative: body.Negative, Description: body.Description, Tags: body.Tags, GoalID: goalID, DebtID: debtID, UserID: user.UID}
+	if body.Category != "" {
+		transaction.CategoryID, err = uuid.Parse(body.Category)
+		if err != nil {
+			c.JSON(400, gin.H{"error": "invalid category uuid"})
+			return
+		}
+	}
+	if body.Priority != "" {
+		transaction.PriorityID, err = uuid.Parse(body.Priority)
+		if err != nil {
+			c.JSON(400, gin.H{"error": "invalid priority uuid"})
+			return
+		}
+	}
+	rules, err := server.store.GetRules(user.UID)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "could not load rules"})
+		return
+	}
+	applyRules(compileRules(rules), &transaction, loc)
+	if transaction.CategoryID == uuid.Nil {
 		c.JSON(400, gin.H{"error": "category not found"})
 		return
 	}
-	cat = category
-	priority, err := server.store.GetPriority(user.UID, &prio)
+	if transaction.PriorityID == uuid.Nil {
+		c.JSON(400, gin.H{"error": "priority not found"})
+		return
+	}
+	cat := models.Category{ID: transaction.CategoryID}
+	prio := models.Priority{ID: transaction.PriorityID}
+	_, err = server.store.GetCategory(user.UID, &cat)
+	if err != nil {
+		c.JSON(400, gin.H{"error": "category not found"})
+		return
+	}
+	_, err = server.store.GetPriority(user.UID, &prio)
 	if err != nil {
 		c.JSON(400, gin.H{"error": "priority not found"})
 		return
 	}
-	prio = priority
-	transaction := models.Transaction{Title: body.Title, CategoryID: cat.ID, PriorityID: prio.ID, Amount: body.Amount, Negative: body.Negative, Description: body.Description, UserID: user.UID}
 	result, err := server.store.CreateTransaction(&transaction)
 	if err != nil {
 		c.JSON(500, gin.H{"error": "Could not create transaction"})
 		return
 	}
+	server.classifiers.Learn(result)
 	c.JSON(200, gin.H{
 		"transaction": result,
+		"anomaly":     server.checkAnomaly(result),
 	})
 }
 
@@ -92,6 +136,9 @@ func (server *Server) TransactionEdit(c *gin.Context) {
 		Negative    bool
 		Description string
 		Priority    string
+		Tags        string
+		Goal        *string
+		Debt        *string
 	}
 	tId := c.Param("id")
 	err := c.BindJSON(&body)
@@ -121,17 +168,33 @@ func (server *Server) TransactionEdit(c *gin.Context) {
 		return
 	}
 	prio = priority
+	// goal and debt links only change when the body names them, an empty
+	// one unlinking the transaction
+	if body.Goal != nil {
+		transaction.GoalID, err = server.checkGoal(c, user, *body.Goal)
+		if err != nil {
+			return
+		}
+	}
+	if body.Debt != nil {
+		transaction.DebtID, err = server.checkDebt(c, user, *body.Debt)
+		if err != nil {
+			return
+		}
+	}
 	transaction.Title = body.Title
 	transaction.CategoryID = cat.ID
 	transaction.PriorityID = prio.ID
 	transaction.Amount = body.Amount
 	transaction.Negative = body.Negative
 	transaction.Description = body.Description
+	transaction.Tags = body.Tags
 	transaction, err = server.store.EditTransaction(&transaction)
 	if err != nil {
 		c.Status(500)
 		return
 	}
+	server.classifiers.Learn(transaction)
 	c.JSON(200, gin.H{
 		"transaction": transaction,
 	})
@@ -145,7 +208,84 @@ func (server *Server) TransactionDelete(c *gin.Context) {
 		c.Status(400)
 		return
 	}
+	server.classifiers.Forget(transaction.ID)
 	c.JSON(200, gin.H{
 		"transaction": transaction,
 	})
 }
+
+// TransactionSuggest ranks the user's categories and priorities for a new
+// transaction using a classifier trained on their own history.
+func (server *Server) TransactionSuggest(c *gin.Context) {
+	title := c.Query("title")
+	if title == "" {
+		c.JSON(400, gin.H{"error": "title is required"})
+		return
+	}
+	amount, err := strconv.Atoi(c.DefaultQuery("amount", "0"))
+	if err != nil {
+		c.JSON(400, gin.H{"error": err.Error()})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "3"))
+	if err != nil || limit <= 0 {
+		limit = 3
+	}
+	user := server.store.GetUserFromToken(c)
+	categorySuggestions, prioritySuggestions, err := server.classifiers.Suggest(user.UID, title, amount, func() ([]models.Transaction, error) {
+		return server.store.GetAllTransactions(user.UID)
+	})
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	categories, err := server.store.GetCategories(user.UID)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	priorities, err := server.store.GetPriorities(user.UID)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	type categorySuggestion struct {
+		CategoryID uuid.UUID
+		Name       string
+		Confidence float64
+	}
+	type prioritySuggestion struct {
+		PriorityID uuid.UUID
+		Name       string
+		Level      int
+		Confidence float64
+	}
+	categoryNames := map[uuid.UUID]string{}
+	for _, cat := range categories {
+		categoryNames[cat.ID] = cat.Name
+	}
+	catResult := []categorySuggestion{}
+	for _, s := range categorySuggestions {
+		name, ok := categoryNames[s.ID]
+		if !ok || len(catResult) == limit {
+			continue
+		}
+		catResult = append(catResult, categorySuggestion{CategoryID: s.ID, Name: name, Confidence: s.Confidence})
+	}
+	priorityByID := map[uuid.UUID]models.Priority{}
+	for _, prio := range priorities {
+		priorityByID[prio.ID] = prio
+	}
+	prioResult := []prioritySuggestion{}
+	for _, s := range prioritySuggestions {
+		prio, ok := priorityByID[s.ID]
+		if !ok || len(prioResult) == limit {
+			continue
+		}
+		prioResult = append(prioResult, prioritySuggestion{PriorityID: s.ID, Name: prio.Name, Level: prio.Level, Confidence: s.Confidence})
+	}
+	c.JSON(200, gin.H{
+		"categories": catResult,
+		"priorities": prioResult,
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/peternabil/go-api/anomaly"
 	mock_store "github.com/peternabil/go-api/mocks"
 	"github.com/peternabil/go-api/models"
 	"github.com/stretchr/testify/require"
@@ -389,6 +390,21 @@ func TestTransactionCreate(t *testing.T) {
 		UserID:      user.UID,
 	}
 
+	quickBody := map[string]interface{}{
+		"Title":    "UBER *TRIP",
+		"Amount":   100,
+		"Negative": true,
+	}
+	rules := []models.Rule{{
+		ID:            uuid.New(),
+		Name:          "rides",
+		TitlePattern:  "^UBER",
+		SetCategoryID: &categoryID,
+		SetPriorityID: &priorityID,
+		SetTitle:      "Uber",
+		UserID:        user.UID,
+	}}
+
 	testCases := []struct {
 		name          string
 		body          map[string]interface{}
@@ -403,10 +419,14 @@ func TestTransactionCreate(t *testing.T) {
 					ReadToken(gomock.Any()).Times(1).Return(user, nil)
 				store.EXPECT().
 					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetRules(gomock.Any()).Times(1).Return([]models.Rule{}, nil)
 				store.EXPECT().
 					GetCategory(gomock.Any(), gomock.Any()).Times(1).Return(category, nil)
 				store.EXPECT().
 					GetPriority(gomock.Any(), gomock.Any()).Times(1).Return(priority, nil)
+				store.EXPECT().
+					GetRecentTransactions(user.UID, gomock.Any(), anomalyHistoryLimit).Times(1).Return([]models.Transaction{}, nil)
 				store.EXPECT().
 					CreateTransaction(gomock.Any()).Times(1).Return(transaction, nil)
 			},
@@ -414,6 +434,85 @@ func TestTransactionCreate(t *testing.T) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 			},
 		},
+		{
+			name: "RulesFillCategoryAndPriority",
+			body: quickBody,
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetRules(gomock.Any()).Times(1).Return(rules, nil)
+				store.EXPECT().
+					GetCategory(gomock.Any(), gomock.Any()).Times(1).Return(category, nil)
+				store.EXPECT().
+					GetPriority(gomock.Any(), gomock.Any()).Times(1).Return(priority, nil)
+				store.EXPECT().
+					GetRecentTransactions(user.UID, gomock.Any(), anomalyHistoryLimit).Times(1).Return([]models.Transaction{}, nil)
+				store.EXPECT().
+					CreateTransaction(gomock.Any()).Times(1).DoAndReturn(func(tr *models.Transaction) (models.Transaction, error) {
+					require.Equal(t, "Uber", tr.Title)
+					require.Equal(t, categoryID, tr.CategoryID)
+					require.Equal(t, priorityID, tr.PriorityID)
+					return *tr, nil
+				})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "UnusualAmount",
+			body: validBody,
+			buildStubs: func(store *mock_store.MockStore) {
+				history := []models.Transaction{}
+				for _, amount := range []int{90, 100, 110, 95, 105, 100} {
+					history = append(history, models.Transaction{CategoryID: categoryID, Amount: amount, UserID: user.UID})
+				}
+				unusual := transaction
+				unusual.Amount = 400
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetRules(gomock.Any()).Times(1).Return([]models.Rule{}, nil)
+				store.EXPECT().
+					GetCategory(gomock.Any(), gomock.Any()).Times(1).Return(category, nil)
+				store.EXPECT().
+					GetPriority(gomock.Any(), gomock.Any()).Times(1).Return(priority, nil)
+				store.EXPECT().
+					GetRecentTransactions(user.UID, gomock.Any(), anomalyHistoryLimit).Times(1).Return(history, nil)
+				store.EXPECT().
+					CreateTransaction(gomock.Any()).Times(1).Return(unusual, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					Anomaly *anomaly.Anomaly `json:"anomaly"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.NotNil(t, res.Anomaly)
+				require.Equal(t, []string{anomaly.ReasonAmount}, res.Anomaly.Reasons)
+				require.Equal(t, int64(100), res.Anomaly.Typical)
+			},
+		},
+		{
+			name: "NoMatchingRuleAndNoCategory",
+			body: quickBody,
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetRules(gomock.Any()).Times(1).Return([]models.Rule{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name: "CategoryNotFound",
 			body: validBody,
@@ -422,6 +521,8 @@ func TestTransactionCreate(t *testing.T) {
 					ReadToken(gomock.Any()).Times(1).Return(user, nil)
 				store.EXPECT().
 					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetRules(gomock.Any()).Times(1).Return([]models.Rule{}, nil)
 				store.EXPECT().
 					GetCategory(gomock.Any(), gomock.Any()).Times(1).Return(models.Category{}, errors.New("category not found"))
 			},
@@ -438,6 +539,8 @@ func TestTransactionCreate(t *testing.T) {
 					ReadToken(gomock.Any()).Times(1).Return(user, nil)
 				store.EXPECT().
 					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetRules(gomock.Any()).Times(1).Return([]models.Rule{}, nil)
 				store.EXPECT().
 					GetCategory(gomock.Any(), gomock.Any()).Times(1).Return(category, nil)
 				store.EXPECT().
@@ -459,6 +562,33 @@ func TestTransactionCreate(t *testing.T) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
+		{
+			name: "HistoryError",
+			body: validBody,
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetRules(gomock.Any()).Times(1).Return([]models.Rule{}, nil)
+				store.EXPECT().
+					GetCategory(gomock.Any(), gomock.Any()).Times(1).Return(category, nil)
+				store.EXPECT().
+					GetPriority(gomock.Any(), gomock.Any()).Times(1).Return(priority, nil)
+				store.EXPECT().
+					CreateTransaction(gomock.Any()).Times(1).Return(transaction, nil)
+				store.EXPECT().
+					GetRecentTransactions(user.UID, gomock.Any(), anomalyHistoryLimit).Times(1).Return(nil, errors.New("db error"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res map[string]any
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Contains(t, res, "anomaly")
+				require.Nil(t, res["anomaly"])
+			},
+		},
 		{
 			name: "InternalServerError",
 			body: validBody,
@@ -467,6 +597,8 @@ func TestTransactionCreate(t *testing.T) {
 					ReadToken(gomock.Any()).Times(1).Return(user, nil)
 				store.EXPECT().
 					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetRules(gomock.Any()).Times(1).Return([]models.Rule{}, nil)
 				store.EXPECT().
 					GetCategory(gomock.Any(), gomock.Any()).Times(1).Return(category, nil)
 				store.EXPECT().
@@ -572,6 +704,16 @@ func TestTransactionEdit(t *testing.T) {
 	updatedTransaction.Negative = validBody["Negative"].(bool)
 	updatedTransaction.Description = validBody["Description"].(string)
 
+	goalID := uuid.New()
+	debtID := uuid.New()
+	linkedTransaction := existingTransaction
+	linkedTransaction.GoalID = &goalID
+	linkedTransaction.DebtID = &debtID
+	unlinkBody := map[string]interface{}{"Goal": "", "Debt": ""}
+	for k, v := range validBody {
+		unlinkBody[k] = v
+	}
+
 	testCases := []struct {
 		name          string
 		body          map[string]interface{}
@@ -601,6 +743,59 @@ func TestTransactionEdit(t *testing.T) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 			},
 		},
+		{
+			name:          "LinksKept",
+			body:          validBody,
+			transactionID: transactionID.String(),
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetTransaction(gomock.Any()).Times(1).Return(linkedTransaction, nil)
+				store.EXPECT().
+					GetCategory(category.ID, gomock.Any()).Times(1).Return(category, nil)
+				store.EXPECT().
+					GetPriority(priority.ID, gomock.Any()).Times(1).Return(priority, nil)
+				store.EXPECT().
+					EditTransaction(gomock.Any()).Times(1).DoAndReturn(func(transaction *models.Transaction) (models.Transaction, error) {
+					require.Equal(t, validBody["Title"], transaction.Title)
+					require.Equal(t, goalID, *transaction.GoalID)
+					require.Equal(t, debtID, *transaction.DebtID)
+					return *transaction, nil
+				})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:          "LinksCleared",
+			body:          unlinkBody,
+			transactionID: transactionID.String(),
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetTransaction(gomock.Any()).Times(1).Return(linkedTransaction, nil)
+				store.EXPECT().
+					GetCategory(category.ID, gomock.Any()).Times(1).Return(category, nil)
+				store.EXPECT().
+					GetPriority(priority.ID, gomock.Any()).Times(1).Return(priority, nil)
+				store.EXPECT().
+					EditTransaction(gomock.Any()).Times(1).DoAndReturn(func(transaction *models.Transaction) (models.Transaction, error) {
+					require.Nil(t, transaction.GoalID)
+					require.Nil(t, transaction.DebtID)
+					return *transaction, nil
+				})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
 		{
 			name:          "TransactionNotFound",
 			body:          validBody,
@@ -786,3 +981,112 @@ func TestTransactionDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestTransactionSuggest(t *testing.T) {
+	password := "Password123"
+	encryptedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
+
+	user := models.User{
+		UID:       uuid.New(),
+		Email:     "user@test.com",
+		FirstName: "test",
+		LastName:  "user",
+		Password:  string(encryptedPass),
+	}
+	rides := models.Category{ID: uuid.New(), Name: "Rides", UserID: user.UID}
+	groceries := models.Category{ID: uuid.New(), Name: "Groceries", UserID: user.UID}
+	low := models.Priority{ID: uuid.New(), Name: "Low", Level: 1, UserID: user.UID}
+	high := models.Priority{ID: uuid.New(), Name: "High", Level: 9, UserID: user.UID}
+	transactions := []models.Transaction{
+		{ID: uuid.New(), Title: "Uber trip home", Amount: 20, CategoryID: rides.ID, PriorityID: low.ID, UserID: user.UID},
+		{ID: uuid.New(), Title: "Uber to airport", Amount: 45, CategoryID: rides.ID, PriorityID: low.ID, UserID: user.UID},
+		{ID: uuid.New(), Title: "Carrefour groceries", Amount: 300, CategoryID: groceries.ID, PriorityID: high.ID, UserID: user.UID},
+		{ID: uuid.New(), Title: "Weekly groceries", Amount: 250, CategoryID: groceries.ID, PriorityID: high.ID, UserID: user.UID},
+	}
+
+	testCases := []struct {
+		name          string
+		param         string
+		buildStubs    func(store *mock_store.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			param: "?title=uber%20ride&amount=30",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetAllTransactions(user.UID).Times(1).Return(transactions, nil)
+				store.EXPECT().
+					GetCategories(user.UID).Times(1).Return([]models.Category{rides, groceries}, nil)
+				store.EXPECT().
+					GetPriorities(user.UID).Times(1).Return([]models.Priority{low, high}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					Categories []struct {
+						CategoryID uuid.UUID
+						Confidence float64
+					} `json:"categories"`
+					Priorities []struct {
+						PriorityID uuid.UUID
+						Confidence float64
+					} `json:"priorities"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Len(t, res.Categories, 2)
+				require.Equal(t, rides.ID, res.Categories[0].CategoryID)
+				require.Greater(t, res.Categories[0].Confidence, 0.5)
+				require.Equal(t, low.ID, res.Priorities[0].PriorityID)
+			},
+		},
+		{
+			name:  "MissingTitle",
+			param: "?amount=30",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InternalServerError",
+			param: "?title=uber",
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					GetAllTransactions(user.UID).Times(1).Return(nil, errors.New("db error"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockStore := mock_store.NewMockStore(mockCtrl)
+			tt.buildStubs(mockStore)
+
+			server, _ := NewServer(mockStore, nil)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/smart-account/api/v1/transaction/suggest%s", tt.param), nil)
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(recorder)
+		})
+	}
+}
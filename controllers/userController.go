@@ -10,6 +10,7 @@ import (
 	"github.com/go-passwd/validator"
 	"github.com/google/uuid"
 	"github.com/peternabil/go-api/models"
+	"github.com/peternabil/go-api/templates"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -42,18 +43,84 @@ func (server *Server) UserFind(c *gin.Context) {
 	})
 }
 
+// UserTimeZone sets the IANA time zone the user's analytics are reported in.
+func (server *Server) UserTimeZone(c *gin.Context) {
+	var body struct {
+		TimeZone string `json:"TimeZone"`
+	}
+	reqErr := c.BindJSON(&body)
+	if reqErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": reqErr.Error()})
+		return
+	}
+	if _, err := loadLocation(body.TimeZone); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	user := server.store.GetUserFromToken(c)
+	user.TimeZone = body.TimeZone
+	res, err := server.store.EditUser(&user)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	c.JSON(200, gin.H{
+		"user": res,
+	})
+}
+
+// UserFiscalMonthStart sets the day of the month the user's month presets
+// start on.
+func (server *Server) UserFiscalMonthStart(c *gin.Context) {
+	var body struct {
+		FiscalMonthStart int `json:"FiscalMonthStart" binding:"min=1,max=28"`
+	}
+	reqErr := c.BindJSON(&body)
+	if reqErr != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": reqErr.Error()})
+		return
+	}
+	user := server.store.GetUserFromToken(c)
+	user.FiscalMonthStart = body.FiscalMonthStart
+	res, err := server.store.EditUser(&user)
+	if err != nil {
+		c.Status(500)
+		return
+	}
+	c.JSON(200, gin.H{
+		"user": res,
+	})
+}
+
 func (server *Server) SignUp(c *gin.Context) {
 	var body struct {
 		Email     string `json:"Email" binding:"required,min=5"`
 		FirstName string `json:"FirstName" binding:"required,min=3"`
 		LastName  string `json:"LastName" binding:"required,min=3"`
 		Password  string `json:"Password" binding:"required,min=6"`
+		Template  string `json:"Template"`
+		Locale    string `json:"Locale"`
+		TimeZone  string `json:"TimeZone"`
 	}
 	reqErr := c.BindJSON(&body)
 	if reqErr != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": reqErr.Error()})
 		return
 	}
+	var categories []models.Category
+	var priorities []models.Priority
+	if body.Template != "" {
+		template, ok := templates.Find(body.Template)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unknown template"})
+			return
+		}
+		categories, priorities = template.Build(uuid.Nil, templates.Locale(body.Locale, c.GetHeader("Accept-Language")))
+	}
+	if _, err := loadLocation(body.TimeZone); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	passwordValid := validator.New(validator.MinLength(6, errors.New("password must be at least 6 chars")), validator.MaxLength(30, errors.New("password must be at most 30 chars")), validator.CommonPassword(errors.New("password cannot be commonly used password")), validator.ContainsAtLeast("abcdefghijklmnopqrstuvwxyz", 5, errors.New("password must contain at least 5 chars")), validator.ContainsAtLeast("_@.()@$#", 1, errors.New("password must contain at least 1 special char _@.()@$#")))
 	err := passwordValid.Validate(body.Password)
 	if err != nil {
@@ -76,8 +143,8 @@ func (server *Server) SignUp(c *gin.Context) {
 		return
 	}
 	fmt.Println(encryptedPass)
-	user := models.User{Email: body.Email, Password: string(encryptedPass), FirstName: body.FirstName, LastName: body.LastName}
-	result, err := server.store.SignUp(&user)
+	user := models.User{Email: body.Email, Password: string(encryptedPass), FirstName: body.FirstName, LastName: body.LastName, TimeZone: body.TimeZone}
+	result, err := server.store.SignUp(&user, categories, priorities)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
 		return
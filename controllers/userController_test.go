@@ -196,7 +196,7 @@ func TestSignUp(t *testing.T) {
 			},
 			buildStubs: func(store *mock_store.MockStore) {
 				store.EXPECT().
-					SignUp(gomock.Any()).Times(1).Return(user, nil)
+					SignUp(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				t.Log(recorder.Body)
@@ -213,7 +213,7 @@ func TestSignUp(t *testing.T) {
 			},
 			buildStubs: func(store *mock_store.MockStore) {
 				store.EXPECT().
-					SignUp(gomock.Any()).Times(1).Return(user, errors.New("server error"))
+					SignUp(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(user, errors.New("server error"))
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				t.Log(recorder.Body)
@@ -229,7 +229,7 @@ func TestSignUp(t *testing.T) {
 			},
 			buildStubs: func(store *mock_store.MockStore) {
 				// store.EXPECT().
-				// 	SignUp(gomock.Any()).Times(1).Return(user, nil)
+				// 	SignUp(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				t.Log(recorder.Body)
@@ -264,6 +264,21 @@ func TestSignUp(t *testing.T) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
+		{
+			name: "unknown template",
+			body: gin.H{
+				"Email":     user.Email,
+				"FirstName": user.FirstName,
+				"LastName":  user.LastName,
+				"Password":  user.Password,
+				"Template":  "pirate",
+			},
+			buildStubs: func(store *mock_store.MockStore) {
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name: "password validation error",
 			body: gin.H{
@@ -274,7 +289,7 @@ func TestSignUp(t *testing.T) {
 			},
 			buildStubs: func(store *mock_store.MockStore) {
 				// store.EXPECT().
-				// SignUp(gomock.Any()).Times(0)
+				// SignUp(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
@@ -413,3 +428,197 @@ func TestLogin(t *testing.T) {
 		})
 	}
 }
+
+func TestUserTimeZone(t *testing.T) {
+	password := "Password123"
+	encryptedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
+
+	user := models.User{
+		UID:       uuid.New(),
+		Email:     "user1@test.com",
+		FirstName: "Test",
+		LastName:  "User1",
+		Password:  string(encryptedPass),
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mock_store.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "success",
+			body: gin.H{"TimeZone": "Europe/Berlin"},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				updated := user
+				updated.TimeZone = "Europe/Berlin"
+				store.EXPECT().
+					EditUser(gomock.Eq(&updated)).Times(1).Return(updated, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				var res struct {
+					User models.User `json:"user"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, "Europe/Berlin", res.User.TimeZone)
+			},
+		},
+		{
+			name: "back to utc",
+			body: gin.H{"TimeZone": ""},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					EditUser(gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "unknown time zone",
+			body: gin.H{"TimeZone": "Mars/Olympus"},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					EditUser(gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "server error",
+			body: gin.H{"TimeZone": "Europe/Berlin"},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					EditUser(gomock.Any()).Times(1).Return(models.User{}, errors.New("db error"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockStore := mock_store.NewMockStore(mockCtrl)
+			tt.buildStubs(mockStore)
+
+			server, _ := NewServer(mockStore, nil)
+			recorder := httptest.NewRecorder()
+
+			body, err := json.Marshal(tt.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest("PUT", "/smart-account/api/v1/users/timezone", bytes.NewReader(body))
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(recorder)
+		})
+	}
+}
+
+func TestUserFiscalMonthStart(t *testing.T) {
+	password := "Password123"
+	encryptedPass, _ := bcrypt.GenerateFromPassword([]byte(password), 10)
+
+	user := models.User{
+		UID:       uuid.New(),
+		Email:     "user1@test.com",
+		FirstName: "Test",
+		LastName:  "User1",
+		Password:  string(encryptedPass),
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mock_store.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "success",
+			body: gin.H{"FiscalMonthStart": 25},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				updated := user
+				updated.FiscalMonthStart = 25
+				store.EXPECT().
+					EditUser(gomock.Eq(&updated)).Times(1).Return(updated, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "day out of range",
+			body: gin.H{"FiscalMonthStart": 31},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					EditUser(gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "server error",
+			body: gin.H{"FiscalMonthStart": 1},
+			buildStubs: func(store *mock_store.MockStore) {
+				store.EXPECT().
+					ReadToken(gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					GetUserFromToken(gomock.Any()).Times(1).Return(user)
+				store.EXPECT().
+					EditUser(gomock.Any()).Times(1).Return(models.User{}, errors.New("db error"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockStore := mock_store.NewMockStore(mockCtrl)
+			tt.buildStubs(mockStore)
+
+			server, _ := NewServer(mockStore, nil)
+			recorder := httptest.NewRecorder()
+
+			body, err := json.Marshal(tt.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest("PUT", "/smart-account/api/v1/users/fiscal-month-start", bytes.NewReader(body))
+			require.NoError(t, err)
+			server.router.ServeHTTP(recorder, request)
+			tt.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package forecast
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/peternabil/go-api/models"
+	"github.com/peternabil/go-api/recurring"
+)
+
+// z is the normal quantile used for the 95% confidence bands.
+const z = 1.96
+
+// Input is everything the forecast is built from. History holds the
+// transactions from HistoryStart up to Start and Current those from Start up
+// to AsOf; both should already be filtered to one sign. Recurring holds the
+// recurring charges the user confirmed that apply to that sign.
+type Input struct {
+	History      []models.Transaction
+	Current      []models.Transaction
+	Recurring    []models.Recurring
+	HistoryStart time.Time
+	Start        time.Time
+	AsOf         time.Time
+	End          time.Time
+}
+
+// Recurring is a transaction expected to repeat before the end of the period.
+type Recurring struct {
+	Title        string
+	CategoryID   uuid.UUID
+	Amount       int64
+	ExpectedDate time.Time
+}
+
+// Projection is the expected end-of-period total with its confidence band.
+type Projection struct {
+	Spent     int64
+	Recurring int64
+	Projected int64
+	Low       int64
+	High      int64
+}
+
+type CategoryProjection struct {
+	CategoryID uuid.UUID
+	Name       string
+	Projection
+}
+
+type Result struct {
+	Total      Projection
+	Categories []CategoryProjection
+	Recurring  []Recurring
+}
+
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// upcoming returns the charges of the confirmed recurring definitions due
+// after AsOf and by End. Charges already made in the current period, even
+// early ones, are taken to be the first ones due in it.
+func upcoming(in Input) []Recurring {
+	paid := map[string]int{}
+	for _, t := range in.Current {
+		paid[recurring.Pattern(t.Title)]++
+	}
+	charges := []Recurring{}
+	for _, r := range in.Recurring {
+		anchor := day(recurring.Anchor(r).In(in.Start.Location()))
+		for _, date := range recurring.Dates(r.Cadence, anchor, in.Start, in.End) {
+			if paid[r.Pattern] > 0 {
+				paid[r.Pattern]--
+				continue
+			}
+			if !date.After(day(in.AsOf)) {
+				continue
+			}
+			charges = append(charges, Recurring{Title: r.Title, CategoryID: r.CategoryID, Amount: int64(r.Amount), ExpectedDate: date})
+		}
+	}
+	sort.Slice(charges, func(i, j int) bool {
+		return charges[i].ExpectedDate.Before(charges[j].ExpectedDate)
+	})
+	return charges
+}
+
+// model holds the weekday-weighted daily average and the daily variance of
+// one series of spending.
+type model struct {
+	average  [7]float64
+	variance float64
+}
+
+func newModel(daily map[time.Time]int64, days []time.Time) model {
+	var m model
+	var counts [7]float64
+	var sum, sumSquares float64
+	for _, d := range days {
+		v := float64(daily[d])
+		m.average[d.Weekday()] += v
+		counts[d.Weekday()]++
+		sum += v
+		sumSquares += v * v
+	}
+	for w := range m.average {
+		if counts[w] > 0 {
+			m.average[w] /= counts[w]
+		}
+	}
+	if n := float64(len(days)); n > 1 {
+		m.variance = (sumSquares - sum*sum/n) / (n - 1)
+	}
+	return m
+}
+
+// project adds the expected spending of the remaining days to spent.
+func (m model) project(spent, recurring int64, remaining []time.Time) Projection {
+	var expected float64
+	for _, d := range remaining {
+		expected += m.average[d.Weekday()]
+	}
+	margin := z * math.Sqrt(m.variance*float64(len(remaining)))
+	return Projection{
+		Spent:     spent,
+		Recurring: recurring,
+		Projected: spent + recurring + int64(math.Round(expected)),
+		Low:       spent + recurring + int64(math.Round(math.Max(expected-margin, 0))),
+		High:      spent + recurring + int64(math.Round(expected+margin)),
+	}
+}
+
+// Forecast projects the totals at End per category and overall from what has
+// been spent so far, weekday-weighted daily averages over the history and
+// the confirmed recurring charges still to come. Past recurring charges are
+// left out of the averages so that they are not counted twice.
+func Forecast(in Input) Result {
+	patterns := map[string]bool{}
+	for _, r := range in.Recurring {
+		patterns[r.Pattern] = true
+	}
+	due := upcoming(in)
+
+	historyDays := []time.Time{}
+	for d := day(in.HistoryStart); d.Before(day(in.Start)); d = d.AddDate(0, 0, 1) {
+		historyDays = append(historyDays, d)
+	}
+	remainingDays := []time.Time{}
+	for d := day(in.AsOf).AddDate(0, 0, 1); !d.After(in.End); d = d.AddDate(0, 0, 1) {
+		remainingDays = append(remainingDays, d)
+	}
+
+	names := map[uuid.UUID]string{}
+	daily := map[uuid.UUID]map[time.Time]int64{}
+	totalDaily := map[time.Time]int64{}
+	for _, t := range in.History {
+		names[t.CategoryID] = t.Category.Name
+		if patterns[recurring.Pattern(t.Title)] {
+			continue
+		}
+		if daily[t.CategoryID] == nil {
+			daily[t.CategoryID] = map[time.Time]int64{}
+		}
+		d := day(t.CreatedAt.In(in.Start.Location()))
+		daily[t.CategoryID][d] += int64(t.Amount)
+		totalDaily[d] += int64(t.Amount)
+	}
+	spent := map[uuid.UUID]int64{}
+	var totalSpent int64
+	for _, t := range in.Current {
+		names[t.CategoryID] = t.Category.Name
+		spent[t.CategoryID] += int64(t.Amount)
+		totalSpent += int64(t.Amount)
+	}
+	dueByCategory := map[uuid.UUID]int64{}
+	var totalDue int64
+	for _, r := range due {
+		if _, ok := names[r.CategoryID]; !ok {
+			names[r.CategoryID] = ""
+		}
+		dueByCategory[r.CategoryID] += r.Amount
+		totalDue += r.Amount
+	}
+
+	result := Result{Categories: []CategoryProjection{}, Recurring: due}
+	for id, name := range names {
+		p := newModel(daily[id], historyDays).project(spent[id], dueByCategory[id], remainingDays)
+		result.Categories = append(result.Categories, CategoryProjection{CategoryID: id, Name: name, Projection: p})
+	}
+	sort.Slice(result.Categories, func(i, j int) bool {
+		return result.Categories[i].Projected > result.Categories[j].Projected
+	})
+	result.Total = newModel(totalDaily, historyDays).project(totalSpent, totalDue, remainingDays)
+	return result
+}
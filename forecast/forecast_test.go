@@ -0,0 +1,108 @@
+package forecast
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/peternabil/go-api/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForecast(t *testing.T) {
+	december := time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)
+	asOf := time.Date(2023, 12, 14, 12, 0, 0, 0, time.UTC)
+	end := december.AddDate(0, 1, 0).Add(-time.Second)
+	food := models.Category{ID: uuid.New(), Name: "Food"}
+	transaction := func(title string, amount int, date time.Time) models.Transaction {
+		t := models.Transaction{Title: title, CategoryID: food.ID, Category: food, Amount: amount, Negative: true}
+		t.CreatedAt = date
+		return t
+	}
+	streaming := models.Recurring{Pattern: "streaming", Title: "Streaming", Cadence: "monthly", Amount: 15,
+		NextDate: time.Date(2023, 10, 20, 0, 0, 0, 0, time.UTC), CategoryID: food.ID}
+	// a charge on the 31st is still due on the 31st after february
+	rent := models.Recurring{Pattern: "rent", Title: "Rent", Cadence: "monthly", Amount: 900,
+		LastDate: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), NextDate: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), CategoryID: food.ID}
+	march := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name  string
+		in    Input
+		check func(t *testing.T, result Result)
+	}{
+		{
+			name: "empty history",
+			in:   Input{HistoryStart: december, Start: december, AsOf: asOf, End: end},
+			check: func(t *testing.T, result Result) {
+				require.Empty(t, result.Categories)
+				require.Empty(t, result.Recurring)
+				require.Equal(t, Projection{}, result.Total)
+			},
+		},
+		{
+			name: "empty history with spending so far",
+			in: Input{
+				Current:      []models.Transaction{transaction("market", 40, december.AddDate(0, 0, 2))},
+				HistoryStart: december, Start: december, AsOf: asOf, End: end,
+			},
+			check: func(t *testing.T, result Result) {
+				require.Len(t, result.Categories, 1)
+				require.Equal(t, Projection{Spent: 40, Projected: 40, Low: 40, High: 40}, result.Total)
+			},
+		},
+		{
+			name: "recurring charge still to come",
+			in: Input{
+				Recurring:    []models.Recurring{streaming},
+				HistoryStart: december, Start: december, AsOf: asOf, End: end,
+			},
+			check: func(t *testing.T, result Result) {
+				require.Len(t, result.Recurring, 1)
+				require.Equal(t, time.Date(2023, 12, 20, 0, 0, 0, 0, time.UTC), result.Recurring[0].ExpectedDate)
+				require.Equal(t, int64(15), result.Total.Projected)
+			},
+		},
+		{
+			name: "recurring charge paid early",
+			in: Input{
+				Current:      []models.Transaction{transaction("STREAMING #123", 15, december.AddDate(0, 0, 3))},
+				Recurring:    []models.Recurring{streaming},
+				HistoryStart: december, Start: december, AsOf: asOf, End: end,
+			},
+			check: func(t *testing.T, result Result) {
+				require.Empty(t, result.Recurring)
+				require.Equal(t, int64(15), result.Total.Projected)
+			},
+		},
+		{
+			name: "past recurring charges stay out of the averages",
+			in: Input{
+				History: []models.Transaction{
+					transaction("streaming", 15, time.Date(2023, 11, 20, 0, 0, 0, 0, time.UTC)),
+				},
+				Recurring:    []models.Recurring{streaming},
+				HistoryStart: december.AddDate(0, -1, 0), Start: december, AsOf: asOf, End: end,
+			},
+			check: func(t *testing.T, result Result) {
+				require.Equal(t, int64(15), result.Total.Projected)
+				require.Equal(t, int64(15), result.Total.High)
+			},
+		},
+		{
+			name: "recurring charge at the end of the month",
+			in: Input{
+				Recurring:    []models.Recurring{rent},
+				HistoryStart: march, Start: march, AsOf: march.AddDate(0, 0, 9), End: march.AddDate(0, 1, 0).Add(-time.Second),
+			},
+			check: func(t *testing.T, result Result) {
+				require.Len(t, result.Recurring, 1)
+				require.Equal(t, time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC), result.Recurring[0].ExpectedDate)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.check(t, Forecast(tt.in))
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package intitializers
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/peternabil/go-api/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// userZone is the SQL for the time zone of user u that rollup days are kept in.
+const userZone = "coalesce(nullif(u.time_zone, ''), 'UTC')"
+
+// rawRollups sums the user's transactions the way they are kept in the
+// rollups.
+const rawRollups = `SELECT t.user_id, date(t.created_at AT TIME ZONE ` + userZone + `) AS day, t.category_id, t.priority_id, t.negative, sum(t.amount) AS total, count(*) AS count
+	FROM transactions t JOIN users u ON u.uid = t.user_id
+	WHERE t.user_id = @user AND t.deleted_at IS NULL
+	GROUP BY 1, 2, 3, 4, 5`
+
+// addRollup adds the transaction, or takes it away for a negative sign, from
+// the rollup of its day and drops the rollups left empty.
+func addRollup(tx *gorm.DB, transaction models.Transaction, sign int64) error {
+	err := tx.Exec(`INSERT INTO daily_rollups (user_id, day, category_id, priority_id, negative, total, count)
+	SELECT u.uid, date(CAST(@at AS timestamptz) AT TIME ZONE `+userZone+`), @category, @priority, @negative, @total, @count
+	FROM users u WHERE u.uid = @user
+	ON CONFLICT (user_id, day, category_id, priority_id, negative)
+	DO UPDATE SET total = daily_rollups.total + excluded.total, count = daily_rollups.count + excluded.count`,
+		sql.Named("user", transaction.UserID), sql.Named("at", transaction.CreatedAt), sql.Named("category", transaction.CategoryID),
+		sql.Named("priority", transaction.PriorityID), sql.Named("negative", transaction.Negative),
+		sql.Named("total", sign*int64(transaction.Amount)), sql.Named("count", sign)).Error
+	if err != nil {
+		return err
+	}
+	return tx.Where("user_id = ? AND count = 0", transaction.UserID).Delete(&models.DailyRollup{}).Error
+}
+
+// saveTransaction saves the transaction and moves it from the rollup of its
+// stored version to the rollup of the saved one.
+func saveTransaction(tx *gorm.DB, transaction *models.Transaction) error {
+	old := models.Transaction{}
+	if err := tx.Where("id = ?", transaction.ID).First(&old).Error; err != nil {
+		return err
+	}
+	if err := addRollup(tx, old, -1); err != nil {
+		return err
+	}
+	if err := tx.Omit(clause.Associations).Save(transaction).Error; err != nil {
+		return err
+	}
+	saved := models.Transaction{}
+	if err := tx.Where("id = ?", transaction.ID).First(&saved).Error; err != nil {
+		return err
+	}
+	return addRollup(tx, saved, 1)
+}
+
+func rebuildRollups(tx *gorm.DB, id uuid.UUID) error {
+	if err := tx.Where("user_id = ?", id).Delete(&models.DailyRollup{}).Error; err != nil {
+		return err
+	}
+	return tx.Exec(`INSERT INTO daily_rollups (user_id, day, category_id, priority_id, negative, total, count) `+rawRollups, sql.Named("user", id)).Error
+}
+
+// RebuildRollups recomputes all of the user's rollups from their transactions.
+func (s MainStore) RebuildRollups(id uuid.UUID) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		return rebuildRollups(tx, id)
+	})
+}
+
+// CheckRollups compares the user's rollups to their transactions and returns
+// every rollup that is missing, left over or off.
+func (s MainStore) CheckRollups(id uuid.UUID) ([]models.RollupMismatch, error) {
+	mismatches := []models.RollupMismatch{}
+	err := DB.Raw(`WITH raw AS (`+rawRollups+`),
+	rollup AS (SELECT * FROM daily_rollups WHERE user_id = @user)
+	SELECT day, category_id, priority_id, negative,
+		coalesce(raw.total, 0) AS raw_total, coalesce(rollup.total, 0) AS rollup_total,
+		coalesce(raw.count, 0) AS raw_count, coalesce(rollup.count, 0) AS rollup_count
+	FROM raw FULL OUTER JOIN rollup USING (day, category_id, priority_id, negative)
+	WHERE raw.total IS DISTINCT FROM rollup.total OR raw.count IS DISTINCT FROM rollup.count
+	ORDER BY day`, sql.Named("user", id)).Scan(&mismatches).Error
+	return mismatches, err
+}
+
+// wholeDays reports whether the range runs from the start of a day to the
+// end of one in the range's time zone.
+func wholeDays(startDate, endDate time.Time) bool {
+	midnight := func(t time.Time) bool {
+		return t.Equal(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()))
+	}
+	return midnight(startDate) && midnight(endDate.Truncate(time.Second).Add(time.Second))
+}
+
+// spendingSource returns a subquery with the user's spending between
+// startDate and endDate per day, category, priority and sign, in columns day,
+// category_id, priority_id, negative, total and count, along with its
+// arguments. It reads the rollups when the range covers whole days in the
+// user's own time zone and the transactions themselves otherwise, so that
+// days are always taken in the time zone of startDate.
+func spendingSource(id uuid.UUID, startDate, endDate time.Time) (string, []any, error) {
+	args := []any{sql.Named("user", id), sql.Named("start", startDate), sql.Named("end", endDate), sql.Named("tz", zoneName(startDate))}
+	if wholeDays(startDate, endDate) {
+		var zone string
+		err := DB.Raw(`SELECT `+userZone+` FROM users u WHERE u.uid = @user`, sql.Named("user", id)).Scan(&zone).Error
+		if err != nil {
+			return "", nil, err
+		}
+		if zone == zoneName(startDate) {
+			args = append(args, sql.Named("start_day", startDate.Format("2006-01-02")), sql.Named("end_day", endDate.Format("2006-01-02")))
+			return `(SELECT day, category_id, priority_id, negative, total, count FROM daily_rollups
+			WHERE user_id = @user AND day BETWEEN @start_day AND @end_day)`, args, nil
+		}
+	}
+	return `(SELECT date(created_at AT TIME ZONE @tz) AS day, category_id, priority_id, negative, amount AS total, 1 AS count FROM transactions
+	WHERE user_id = @user AND created_at BETWEEN @start AND @end AND deleted_at IS NULL)`, args, nil
+}
@@ -1,7 +1,9 @@
 package intitializers
 
 import (
+	"database/sql"
 	"errors"
+	"fmt"
 	"os"
 	"strings"
 	"time"
@@ -10,6 +12,7 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/peternabil/go-api/models"
+	"github.com/peternabil/go-api/reports"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -74,15 +77,35 @@ func (s MainStore) GetUserFromToken(c *gin.Context) models.User {
 	return c.MustGet("user").(models.User)
 }
 func (s MainStore) CreateTransaction(transaction *models.Transaction) (models.Transaction, error) {
-	err := DB.Create(transaction).Error
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(transaction).Error; err != nil {
+			return err
+		}
+		return addRollup(tx, *transaction, 1)
+	})
 	return *transaction, err
 }
 func (s MainStore) EditTransaction(transaction *models.Transaction) (models.Transaction, error) {
-	err := DB.Save(&transaction).Error
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		return saveTransaction(tx, transaction)
+	})
 	return *transaction, err
 }
 func (s MainStore) DeleteTransaction(transaction *models.Transaction) error {
-	return DB.Delete(&transaction).Error
+	return DB.Transaction(func(tx *gorm.DB) error {
+		old := models.Transaction{}
+		res := tx.Where("id = ?", transaction.ID).Limit(1).Find(&old)
+		if res.Error != nil {
+			return res.Error
+		}
+		if err := tx.Delete(&transaction).Error; err != nil {
+			return err
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		return addRollup(tx, old, -1)
+	})
 }
 func (s MainStore) GetTransaction(transaction *models.Transaction) (models.Transaction, error) {
 	err := DB.Preload(clause.Associations).First(&transaction).Error
@@ -94,6 +117,47 @@ func (s MainStore) GetTransactions(id uuid.UUID, page, pageSize int, count *int6
 	err := DB.Preload(clause.Associations).Order("created_at desc").Limit(pageSize).Offset((page-1)*pageSize).Where("user_id = ?", id).Find(&transactions).Error
 	return transactions, err
 }
+func (s MainStore) GetTransactionsDateRange(id uuid.UUID, startDate, endDate time.Time) ([]models.Transaction, error) {
+	transactions := []models.Transaction{}
+	err := DB.Preload(clause.Associations).Where("user_id = ? AND created_at BETWEEN ? AND ?", id, startDate, endDate).Order("created_at asc").Find(&transactions).Error
+	return transactions, err
+}
+
+// GetBalance returns the net of all income minus all expenses recorded before date.
+func (s MainStore) GetBalance(id uuid.UUID, date time.Time) (int64, error) {
+	var balance int64
+	err := DB.Model(&models.Transaction{}).Select("coalesce(sum(CASE WHEN negative THEN -amount ELSE amount END), 0)").Where("user_id = ? AND created_at < ?", id, date).Scan(&balance).Error
+	return balance, err
+}
+func (s MainStore) GetAllTransactions(id uuid.UUID) ([]models.Transaction, error) {
+	transactions := []models.Transaction{}
+	err := DB.Preload(clause.Associations).Where("user_id = ?", id).Order("created_at asc").Find(&transactions).Error
+	return transactions, err
+}
+
+// GetRecentTransactions returns at most limit of the user's latest
+// transactions made since the given time, oldest first, without their
+// associations.
+func (s MainStore) GetRecentTransactions(id uuid.UUID, since time.Time, limit int) ([]models.Transaction, error) {
+	transactions := []models.Transaction{}
+	err := DB.Where("user_id = ? AND created_at >= ?", id, since).Order("created_at desc").Limit(limit).Find(&transactions).Error
+	for i, j := 0, len(transactions)-1; i < j; i, j = i+1, j-1 {
+		transactions[i], transactions[j] = transactions[j], transactions[i]
+	}
+	return transactions, err
+}
+
+// EditTransactions saves all the given transactions in a single database transaction.
+func (s MainStore) EditTransactions(transactions []models.Transaction) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		for i := range transactions {
+			if err := saveTransaction(tx, &transactions[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
 func (s MainStore) CreateCategory(category *models.Category) (models.Category, error) {
 	err := DB.Create(&category).Error
 	return *category, err
@@ -102,8 +166,19 @@ func (s MainStore) EditCategory(category *models.Category) (models.Category, err
 	err := DB.Save(&category).Error
 	return *category, err
 }
+
+// DeleteCategory deletes the category and moves its children up to its parent.
 func (s MainStore) DeleteCategory(category *models.Category) error {
-	return DB.Delete(&category).Error
+	return DB.Transaction(func(tx *gorm.DB) error {
+		existing := models.Category{}
+		if err := tx.Where("id = ?", category.ID).First(&existing).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Category{}).Where("parent_id = ?", existing.ID).Update("parent_id", existing.ParentID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&existing).Error
+	})
 }
 func (s MainStore) GetCategory(id uuid.UUID, category *models.Category) (models.Category, error) {
 	err := DB.Preload(clause.Associations).Where("user_id = ?", id).First(&category).Error
@@ -115,6 +190,84 @@ func (s MainStore) GetCategories(id uuid.UUID) ([]models.Category, error) {
 	return categories, err
 }
 
+// MergeCategories moves everything that references source over to target and
+// deletes source, all in one database transaction.
+func (s MainStore) MergeCategories(id uuid.UUID, source, target *models.Category) (models.MergeResult, error) {
+	result := models.MergeResult{}
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.Transaction{}).Where("user_id = ? AND category_id = ?", id, source.ID).Update("category_id", target.ID)
+		if res.Error != nil {
+			return res.Error
+		}
+		result.Transactions = res.RowsAffected
+		res = tx.Model(&models.Rule{}).Where("user_id = ? AND set_category_id = ?", id, source.ID).Update("set_category_id", target.ID)
+		if res.Error != nil {
+			return res.Error
+		}
+		result.Rules = res.RowsAffected
+		res = tx.Model(&models.Budget{}).Where("user_id = ? AND category_id = ?", id, source.ID).Update("category_id", target.ID)
+		if res.Error != nil {
+			return res.Error
+		}
+		result.Budgets = res.RowsAffected
+		// the envelope history is left as it was; every month source was
+		// credited in gets a merge event handing its net over to target
+		res = tx.Exec(`INSERT INTO envelope_events (created_at, updated_at, kind, month, category_id, from_category_id, amount, note, user_id)
+		SELECT now(), now(), 'merge', e.month, @target, @source, sum(e.amount), @note, @user FROM (
+			SELECT month, amount FROM envelope_events WHERE user_id = @user AND category_id = @source AND deleted_at IS NULL
+			UNION ALL
+			SELECT month, -amount FROM envelope_events WHERE user_id = @user AND from_category_id = @source AND deleted_at IS NULL
+		) e GROUP BY e.month HAVING sum(e.amount) <> 0`,
+			sql.Named("user", id), sql.Named("source", source.ID), sql.Named("target", target.ID), sql.Named("note", "merged from "+source.Name))
+		if res.Error != nil {
+			return res.Error
+		}
+		result.Envelopes = res.RowsAffected
+		res = tx.Model(&models.Recurring{}).Where("user_id = ? AND category_id = ?", id, source.ID).Update("category_id", target.ID)
+		if res.Error != nil {
+			return res.Error
+		}
+		result.Recurring = res.RowsAffected
+		res = tx.Model(&models.Goal{}).Where("user_id = ? AND category_id = ?", id, source.ID).Update("category_id", target.ID)
+		if res.Error != nil {
+			return res.Error
+		}
+		result.Goals = res.RowsAffected
+		res = tx.Model(&models.Report{}).Where("user_id = ? AND category_id = ?", id, source.ID).Update("category_id", target.ID)
+		if res.Error != nil {
+			return res.Error
+		}
+		result.Reports = res.RowsAffected
+		// lift target out of source's subtree so re-parenting cannot create a cycle
+		categories := []models.Category{}
+		if err := tx.Where("user_id = ?", id).Find(&categories).Error; err != nil {
+			return err
+		}
+		parents := map[uuid.UUID]*uuid.UUID{}
+		for _, cat := range categories {
+			parents[cat.ID] = cat.ParentID
+		}
+		for current := parents[target.ID]; current != nil; current = parents[*current] {
+			if *current == source.ID {
+				if err := tx.Model(&models.Category{}).Where("id = ?", target.ID).Update("parent_id", source.ParentID).Error; err != nil {
+					return err
+				}
+				break
+			}
+		}
+		res = tx.Model(&models.Category{}).Where("user_id = ? AND parent_id = ? AND id <> ?", id, source.ID, target.ID).Update("parent_id", target.ID)
+		if res.Error != nil {
+			return res.Error
+		}
+		result.Categories = res.RowsAffected
+		if err := rebuildRollups(tx, id); err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", id).Delete(source).Error
+	})
+	return result, err
+}
+
 func (s MainStore) CreatePriority(priority *models.Priority) (models.Priority, error) {
 	err := DB.Create(&priority).Error
 	return *priority, err
@@ -137,6 +290,228 @@ func (s MainStore) GetPriorities(id uuid.UUID) ([]models.Priority, error) {
 	return priorities, err
 }
 
+func (s MainStore) CreateRule(rule *models.Rule) (models.Rule, error) {
+	err := DB.Create(&rule).Error
+	return *rule, err
+}
+func (s MainStore) EditRule(rule *models.Rule) (models.Rule, error) {
+	err := DB.Save(&rule).Error
+	return *rule, err
+}
+func (s MainStore) DeleteRule(rule *models.Rule) error {
+	return DB.Where("user_id = ?", rule.UserID).Delete(&rule).Error
+}
+func (s MainStore) GetRule(id uuid.UUID, rule *models.Rule) (models.Rule, error) {
+	err := DB.Where("user_id = ?", id).First(&rule).Error
+	return *rule, err
+}
+func (s MainStore) GetRules(id uuid.UUID) ([]models.Rule, error) {
+	rules := []models.Rule{}
+	err := DB.Where("user_id = ?", id).Order("position asc, created_at asc").Find(&rules).Error
+	return rules, err
+}
+
+// MergePriorities moves everything that references source over to target and
+// deletes source, all in one database transaction.
+func (s MainStore) MergePriorities(id uuid.UUID, source, target *models.Priority) (models.MergeResult, error) {
+	result := models.MergeResult{}
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.Transaction{}).Where("user_id = ? AND priority_id = ?", id, source.ID).Update("priority_id", target.ID)
+		if res.Error != nil {
+			return res.Error
+		}
+		result.Transactions = res.RowsAffected
+		res = tx.Model(&models.Rule{}).Where("user_id = ? AND set_priority_id = ?", id, source.ID).Update("set_priority_id", target.ID)
+		if res.Error != nil {
+			return res.Error
+		}
+		result.Rules = res.RowsAffected
+		res = tx.Model(&models.Budget{}).Where("user_id = ? AND priority_id = ?", id, source.ID).Update("priority_id", target.ID)
+		if res.Error != nil {
+			return res.Error
+		}
+		result.Budgets = res.RowsAffected
+		res = tx.Model(&models.Report{}).Where("user_id = ? AND priority_id = ?", id, source.ID).Update("priority_id", target.ID)
+		if res.Error != nil {
+			return res.Error
+		}
+		result.Reports = res.RowsAffected
+		if err := rebuildRollups(tx, id); err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", id).Delete(source).Error
+	})
+	return result, err
+}
+
+func (s MainStore) CreateBudget(budget *models.Budget) (models.Budget, error) {
+	err := DB.Create(&budget).Error
+	return *budget, err
+}
+func (s MainStore) EditBudget(budget *models.Budget) (models.Budget, error) {
+	err := DB.Save(&budget).Error
+	return *budget, err
+}
+func (s MainStore) DeleteBudget(budget *models.Budget) error {
+	return DB.Where("user_id = ?", budget.UserID).Delete(&budget).Error
+}
+func (s MainStore) GetBudget(id uuid.UUID, budget *models.Budget) (models.Budget, error) {
+	err := DB.Where("user_id = ?", id).First(&budget).Error
+	return *budget, err
+}
+func (s MainStore) GetBudgets(id uuid.UUID) ([]models.Budget, error) {
+	budgets := []models.Budget{}
+	err := DB.Where("user_id = ?", id).Order("created_at asc").Find(&budgets).Error
+	return budgets, err
+}
+
+func (s MainStore) CreateGoal(goal *models.Goal) (models.Goal, error) {
+	err := DB.Create(&goal).Error
+	return *goal, err
+}
+func (s MainStore) EditGoal(goal *models.Goal) (models.Goal, error) {
+	err := DB.Save(&goal).Error
+	return *goal, err
+}
+
+// DeleteGoal unlinks the goal's contributions and deletes it.
+func (s MainStore) DeleteGoal(goal *models.Goal) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Transaction{}).Where("user_id = ? AND goal_id = ?", goal.UserID, goal.ID).Update("goal_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", goal.UserID).Delete(&goal).Error
+	})
+}
+func (s MainStore) GetGoal(id uuid.UUID, goal *models.Goal) (models.Goal, error) {
+	err := DB.Where("user_id = ?", id).First(&goal).Error
+	return *goal, err
+}
+func (s MainStore) GetGoals(id uuid.UUID) ([]models.Goal, error) {
+	goals := []models.Goal{}
+	err := DB.Where("user_id = ?", id).Order("target_date asc").Find(&goals).Error
+	return goals, err
+}
+
+// GetGoalTransactions returns the transactions that count towards the goal,
+// oldest first.
+func (s MainStore) GetGoalTransactions(id uuid.UUID, goal *models.Goal) ([]models.Transaction, error) {
+	transactions := []models.Transaction{}
+	query := DB.Where("goal_id = ?", goal.ID)
+	if goal.CategoryID != nil {
+		query = query.Or("category_id = ?", *goal.CategoryID)
+	}
+	err := DB.Where("user_id = ?", id).Where(query).Order("created_at asc").Find(&transactions).Error
+	return transactions, err
+}
+
+func (s MainStore) CreateDebt(debt *models.Debt) (models.Debt, error) {
+	err := DB.Create(&debt).Error
+	return *debt, err
+}
+func (s MainStore) EditDebt(debt *models.Debt) (models.Debt, error) {
+	err := DB.Save(&debt).Error
+	return *debt, err
+}
+
+// DeleteDebt unlinks the debt's payments and deletes it.
+func (s MainStore) DeleteDebt(debt *models.Debt) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Transaction{}).Where("user_id = ? AND debt_id = ?", debt.UserID, debt.ID).Update("debt_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", debt.UserID).Delete(&debt).Error
+	})
+}
+func (s MainStore) GetDebt(id uuid.UUID, debt *models.Debt) (models.Debt, error) {
+	err := DB.Where("user_id = ?", id).First(&debt).Error
+	return *debt, err
+}
+func (s MainStore) GetDebts(id uuid.UUID) ([]models.Debt, error) {
+	debts := []models.Debt{}
+	err := DB.Where("user_id = ?", id).Order("created_at asc").Find(&debts).Error
+	return debts, err
+}
+
+func (s MainStore) CreateRecurring(recurring *models.Recurring) (models.Recurring, error) {
+	err := DB.Create(&recurring).Error
+	return *recurring, err
+}
+func (s MainStore) DeleteRecurring(recurring *models.Recurring) error {
+	return DB.Where("user_id = ?", recurring.UserID).Delete(&recurring).Error
+}
+func (s MainStore) GetRecurring(id uuid.UUID, recurring *models.Recurring) (models.Recurring, error) {
+	err := DB.Where("user_id = ?", id).First(&recurring).Error
+	return *recurring, err
+}
+func (s MainStore) GetRecurrings(id uuid.UUID) ([]models.Recurring, error) {
+	recurrings := []models.Recurring{}
+	err := DB.Where("user_id = ?", id).Order("created_at asc").Find(&recurrings).Error
+	return recurrings, err
+}
+
+// GetDebtPayments returns the net amount paid towards each of the user's
+// debts, keyed by debt id.
+func (s MainStore) GetDebtPayments(id uuid.UUID) (map[uuid.UUID]int64, error) {
+	rows := []struct {
+		DebtID uuid.UUID
+		Total  int64
+	}{}
+	err := DB.Model(&models.Transaction{}).Select("debt_id, sum(CASE WHEN negative THEN amount ELSE -amount END) as total").Where("user_id = ? AND debt_id IS NOT NULL", id).Group("debt_id").Scan(&rows).Error
+	payments := map[uuid.UUID]int64{}
+	for _, row := range rows {
+		payments[row.DebtID] = row.Total
+	}
+	return payments, err
+}
+
+func (s MainStore) CreateReport(report *models.Report) (models.Report, error) {
+	err := DB.Create(&report).Error
+	return *report, err
+}
+func (s MainStore) EditReport(report *models.Report) (models.Report, error) {
+	err := DB.Save(&report).Error
+	return *report, err
+}
+func (s MainStore) DeleteReport(report *models.Report) error {
+	return DB.Where("user_id = ?", report.UserID).Delete(&report).Error
+}
+func (s MainStore) GetReport(id uuid.UUID, report *models.Report) (models.Report, error) {
+	err := DB.Where("user_id = ?", id).First(&report).Error
+	return *report, err
+}
+func (s MainStore) GetReports(id uuid.UUID) ([]models.Report, error) {
+	reports := []models.Report{}
+	err := DB.Where("user_id = ?", id).Order("name asc").Find(&reports).Error
+	return reports, err
+}
+
+// RunReport compiles the report and runs it over the user's transactions
+// between startDate and endDate, with months and weekdays taken in the time
+// zone of startDate. Each row maps column names to values.
+func (s MainStore) RunReport(id uuid.UUID, report models.Report, startDate, endDate time.Time) ([]map[string]any, error) {
+	rows := []map[string]any{}
+	query, args, err := reports.Compile(report, id, startDate, endDate, zoneName(startDate))
+	if err != nil {
+		return rows, err
+	}
+	err = DB.Raw(query, args...).Scan(&rows).Error
+	return rows, err
+}
+
+func (s MainStore) CreateEnvelopeEvent(event *models.EnvelopeEvent) (models.EnvelopeEvent, error) {
+	err := DB.Create(&event).Error
+	return *event, err
+}
+
+// GetEnvelopeEvents returns the user's envelope events for the months between
+// startDate and endDate, oldest first.
+func (s MainStore) GetEnvelopeEvents(id uuid.UUID, startDate, endDate time.Time) ([]models.EnvelopeEvent, error) {
+	events := []models.EnvelopeEvent{}
+	err := DB.Where("user_id = ? AND month BETWEEN ? AND ?", id, startDate, endDate).Order("created_at asc").Find(&events).Error
+	return events, err
+}
+
 func (s MainStore) GetUsers() ([]models.User, error) {
 	users := []models.User{}
 	err := DB.Find(&users).Error
@@ -146,36 +521,293 @@ func (s MainStore) GetUser(user *models.User) (models.User, error) {
 	err := DB.First(&user).Error
 	return *user, err
 }
-func (s MainStore) SignUp(user *models.User) (models.User, error) {
-	err := DB.Create(&user).Error
+
+// EditUser saves the user. A new time zone moves every transaction to the
+// day it falls on there, so the user's rollups are rebuilt along with it.
+func (s MainStore) EditUser(user *models.User) (models.User, error) {
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		old := models.User{}
+		if err := tx.Where("uid = ?", user.UID).First(&old).Error; err != nil {
+			return err
+		}
+		if err := tx.Save(&user).Error; err != nil {
+			return err
+		}
+		if old.TimeZone == user.TimeZone {
+			return nil
+		}
+		return rebuildRollups(tx, user.UID)
+	})
+	return *user, err
+}
+
+// SignUp creates the user together with any starter categories and
+// priorities in one database transaction.
+func (s MainStore) SignUp(user *models.User, categories []models.Category, priorities []models.Priority) (models.User, error) {
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		for i := range categories {
+			categories[i].UserID = user.UID
+		}
+		for i := range priorities {
+			priorities[i].UserID = user.UID
+		}
+		return seed(tx, categories, priorities)
+	})
 	return *user, err
 }
+
+// SeedCategoriesAndPriorities creates all the given rows or none of them.
+func (s MainStore) SeedCategoriesAndPriorities(categories []models.Category, priorities []models.Priority) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		return seed(tx, categories, priorities)
+	})
+}
+
+func seed(tx *gorm.DB, categories []models.Category, priorities []models.Priority) error {
+	if len(categories) > 0 {
+		if err := tx.Create(&categories).Error; err != nil {
+			return err
+		}
+	}
+	if len(priorities) > 0 {
+		return tx.Create(&priorities).Error
+	}
+	return nil
+}
 func (s MainStore) FindUser(email string) (models.User, error) {
 	user := models.User{}
 	err := DB.Where("email = ?", email).First(&user).Error
 	return user, err
 }
 
+// zoneName names the time zone of t for Postgres' AT TIME ZONE. Zones without
+// an IANA name are written as POSIX offsets, which count west of UTC.
+func zoneName(t time.Time) string {
+	if name := t.Location().String(); name != "" && name != "Local" {
+		return name
+	}
+	_, offset := t.Zone()
+	sign := "-"
+	if offset < 0 {
+		sign, offset = "+", -offset
+	}
+	return fmt.Sprintf("UTC%s%02d:%02d", sign, offset/3600, offset/60%60)
+}
+
+// GetTransactionsDateRangeGroupByDay sums transactions per day, with days
+// taken in the time zone of startDate.
 func (s MainStore) GetTransactionsDateRangeGroupByDay(id uuid.UUID, startDate, endDate time.Time, negative bool) ([]models.Spending, error) {
 	spendings := []models.Spending{}
-	err := DB.Table("transactions").Select("date(created_at) as date, sum(amount) as total, negative as Negative").Where("user_id = ?", id).Group("date(created_at), negative").Having("date(created_at) BETWEEN ? AND ? AND negative = ?", startDate, endDate, negative).Order("date(created_at) ASC").Scan(&spendings).Error
+	source, args, err := spendingSource(id, startDate, endDate)
+	if err != nil {
+		return spendings, err
+	}
+	err = DB.Raw(`SELECT s.day AS date, sum(s.total) AS total, s.negative FROM `+source+` s
+	WHERE s.negative = @negative GROUP BY 1, s.negative ORDER BY 1`,
+		append(args, sql.Named("negative", negative))...).Scan(&spendings).Error
+	return spendings, err
+}
+
+// GetHighestSpendingCategory sums spending per category. With depth 0 every
+// category is reported on its own; otherwise categories nested deeper than
+// depth are rolled up into their ancestor at that depth (1 being the roots).
+func (s MainStore) GetHighestSpendingCategory(id uuid.UUID, startDate, endDate time.Time, negative bool, depth int) ([]models.SpendingCategory, error) {
+	spendings := []models.SpendingCategory{}
+	source, args, err := spendingSource(id, startDate, endDate)
+	if err != nil {
+		return spendings, err
+	}
+	args = append(args, sql.Named("negative", negative))
+	if depth <= 0 {
+		err = DB.Raw(`SELECT sum(s.total) AS total, s.category_id, c.name AS cname
+		FROM `+source+` s JOIN categories c ON c.id = s.category_id
+		WHERE s.negative = @negative GROUP BY s.category_id, c.name ORDER BY total DESC`, args...).Scan(&spendings).Error
+		return spendings, err
+	}
+	err = DB.Raw(`WITH RECURSIVE tree AS (
+		SELECT id, id AS root_id, name AS root_name, 1 AS depth FROM categories WHERE user_id = @user AND parent_id IS NULL AND deleted_at IS NULL
+		UNION ALL
+		SELECT c.id, CASE WHEN tree.depth < @depth THEN c.id ELSE tree.root_id END, CASE WHEN tree.depth < @depth THEN c.name ELSE tree.root_name END, tree.depth + 1
+		FROM categories c JOIN tree ON c.parent_id = tree.id WHERE c.deleted_at IS NULL
+	)
+	SELECT sum(s.total) AS total, tree.root_id AS category_id, tree.root_name AS cname
+	FROM `+source+` s JOIN tree ON tree.id = s.category_id
+	WHERE s.negative = @negative
+	GROUP BY tree.root_id, tree.root_name ORDER BY total DESC`,
+		append(args, sql.Named("depth", depth))...).Scan(&spendings).Error
 	return spendings, err
 }
 
-func (s MainStore) GetHighestSpendingCategory(id uuid.UUID, startDate, endDate time.Time, negative bool) ([]models.SpendingCategory, error) {
+// GetMonthlySpendingCategory sums transactions per category and calendar
+// month; Date holds the first day of each month. Months are taken in the
+// time zone of startDate.
+func (s MainStore) GetMonthlySpendingCategory(id uuid.UUID, startDate, endDate time.Time, negative bool) ([]models.SpendingCategory, error) {
 	spendings := []models.SpendingCategory{}
-	err := DB.Preload(clause.Associations).Table("transactions t , categories c").Select("sum(amount) as total, category_id, c.name as CName").Where("t.user_id = ? AND t.created_at BETWEEN ? AND ? AND negative = ? AND c.id = category_id", id, startDate, endDate, negative).Group("category_id, c.name").Order("total desc").Scan(&spendings).Error
+	source, args, err := spendingSource(id, startDate, endDate)
+	if err != nil {
+		return spendings, err
+	}
+	err = DB.Raw(`SELECT date_trunc('month', CAST(s.day AS timestamp)) AS date, sum(s.total) AS total, s.category_id, s.negative
+	FROM `+source+` s WHERE s.negative = @negative
+	GROUP BY 1, s.category_id, s.negative ORDER BY 1`,
+		append(args, sql.Named("negative", negative))...).Scan(&spendings).Error
 	return spendings, err
 }
 
-func (s MainStore) GetHighestSpendingPriority(id uuid.UUID, startDate, endDate time.Time, negative bool) ([]models.SpendingPriority, error) {
+// GetMonthlySpendingLevel sums income and expenses per calendar month and
+// priority level; transactions without a priority count as level 0. Months
+// are taken in the time zone of startDate.
+func (s MainStore) GetMonthlySpendingLevel(id uuid.UUID, startDate, endDate time.Time) ([]models.SpendingPriority, error) {
 	spendings := []models.SpendingPriority{}
-	err := DB.Preload(clause.Associations).Table("transactions t , priorities p").Select("sum(amount) as total, priority_id, p.name as PName, p.level as Level").Where("t.user_id = ? AND t.created_at BETWEEN ? AND ? AND negative = ? AND p.id = priority_id", id, startDate, endDate, negative).Group("priority_id, p.name, p.level").Order("total desc").Scan(&spendings).Error
+	source, args, err := spendingSource(id, startDate, endDate)
+	if err != nil {
+		return spendings, err
+	}
+	err = DB.Raw(`SELECT date_trunc('month', CAST(s.day AS timestamp)) AS date, coalesce(p.level, 0) AS level, s.negative, sum(s.total) AS total
+	FROM `+source+` s LEFT JOIN priorities p ON p.id = s.priority_id
+	GROUP BY 1, 2, 3 ORDER BY 1`, args...).Scan(&spendings).Error
 	return spendings, err
 }
 
-func (s MainStore) TotalSpending(id uuid.UUID, startDate, endDate time.Time, negative bool) ([]models.SpendingPriority, error) {
+// groupTable returns the table and transaction column to group by for
+// category or priority rows.
+func groupTable(rows string) (string, string) {
+	if rows == "priority" {
+		return "priorities", "priority_id"
+	}
+	return "categories", "category_id"
+}
+
+// GetSpendingPivot sums transactions per category or priority (rows) and per
+// date_trunc unit in one grouped query, with buckets taken in the time zone of
+// startDate.
+func (s MainStore) GetSpendingPivot(id uuid.UUID, startDate, endDate time.Time, negative bool, rows, unit string) ([]models.PivotCell, error) {
+	table, column := groupTable(rows)
+	cells := []models.PivotCell{}
+	source, args, err := spendingSource(id, startDate, endDate)
+	if err != nil {
+		return cells, err
+	}
+	err = DB.Raw(fmt.Sprintf(`SELECT date_trunc(@unit, CAST(s.day AS timestamp)) AS date, r.id, r.name, sum(s.total) AS total
+	FROM %s s JOIN %s r ON r.id = s.%s
+	WHERE s.negative = @negative
+	GROUP BY 1, r.id, r.name ORDER BY 1`, source, table, column),
+		append(args, sql.Named("unit", unit), sql.Named("negative", negative))...).Scan(&cells).Error
+	return cells, err
+}
+
+// GetSpendingStats describes the transaction amounts per category or
+// priority (rows), biggest totals first.
+func (s MainStore) GetSpendingStats(id uuid.UUID, startDate, endDate time.Time, negative bool, rows string) ([]models.SpendingStats, error) {
+	table, column := groupTable(rows)
+	stats := []models.SpendingStats{}
+	err := DB.Raw(fmt.Sprintf(`SELECT r.id, r.name, count(*) AS count, sum(t.amount) AS total, avg(t.amount) AS mean,
+		percentile_cont(0.5) WITHIN GROUP (ORDER BY t.amount) AS median,
+		percentile_cont(0.9) WITHIN GROUP (ORDER BY t.amount) AS p90,
+		min(t.amount) AS min, max(t.amount) AS max
+	FROM transactions t JOIN %s r ON r.id = t.%s
+	WHERE t.user_id = @user AND t.created_at BETWEEN @start AND @end AND t.negative = @negative AND t.deleted_at IS NULL
+	GROUP BY r.id, r.name ORDER BY total DESC`, table, column),
+		sql.Named("user", id), sql.Named("start", startDate), sql.Named("end", endDate), sql.Named("negative", negative)).Scan(&stats).Error
+	return stats, err
+}
+
+// GetSpendingPatterns sums transactions per day of the week (0 being Sunday)
+// and hour of the day, both read off the time of each transaction in the time
+// zone of startDate. A nil category or priority matches every transaction.
+func (s MainStore) GetSpendingPatterns(id uuid.UUID, startDate, endDate time.Time, negative bool, categoryID, priorityID *uuid.UUID) ([]models.SpendingPattern, error) {
+	patterns := []models.SpendingPattern{}
+	tz := zoneName(startDate)
+	query := DB.Table("transactions").Select("CAST(extract(dow FROM created_at AT TIME ZONE ?) AS int) AS weekday, CAST(extract(hour FROM created_at AT TIME ZONE ?) AS int) AS hour, sum(amount) AS total, count(*) AS count", tz, tz).Where("user_id = ? AND created_at BETWEEN ? AND ? AND negative = ? AND deleted_at IS NULL", id, startDate, endDate, negative)
+	if categoryID != nil {
+		query = query.Where("category_id = ?", *categoryID)
+	}
+	if priorityID != nil {
+		query = query.Where("priority_id = ?", *priorityID)
+	}
+	err := query.Group("1, 2").Order("1, 2").Scan(&patterns).Error
+	return patterns, err
+}
+
+func (s MainStore) GetHighestSpendingPriority(id uuid.UUID, startDate, endDate time.Time, negative bool) ([]models.SpendingPriority, error) {
 	spendings := []models.SpendingPriority{}
-	err := DB.Preload(clause.Associations).Table("transactions t , priorities p").Select("sum(amount) as total, priority_id, p.name as PName, p.level as Level").Where("t.user_id = ? AND t.created_at BETWEEN ? AND ? AND negative = ? AND p.id = priority_id", id, startDate, endDate, negative).Group("priority_id, p.name, p.level").Order("total desc").Scan(&spendings).Error
+	source, args, err := spendingSource(id, startDate, endDate)
+	if err != nil {
+		return spendings, err
+	}
+	err = DB.Raw(`SELECT sum(s.total) AS total, s.priority_id, p.name AS pname, p.level
+	FROM `+source+` s JOIN priorities p ON p.id = s.priority_id
+	WHERE s.negative = @negative GROUP BY s.priority_id, p.name, p.level ORDER BY total DESC`,
+		append(args, sql.Named("negative", negative))...).Scan(&spendings).Error
 	return spendings, err
 }
+
+// TotalSpending summarizes the period. Totals, per-category and per-priority
+// sums all come out of one pass over the daily spending using grouping sets;
+// only the largest expense needs a second query.
+func (s MainStore) TotalSpending(id uuid.UUID, startDate, endDate time.Time) (models.Summary, error) {
+	summary := models.Summary{TopCategories: []models.SpendingCategory{}, TopPriorities: []models.SpendingPriority{}}
+	rows := []struct {
+		CategoryID    *uuid.UUID
+		Cname         *string
+		PriorityID    *uuid.UUID
+		Pname         *string
+		Level         *int
+		Income        int64
+		Expense       int64
+		Count         int64
+		GroupCategory int
+		GroupPriority int
+	}{}
+	source, args, err := spendingSource(id, startDate, endDate)
+	if err != nil {
+		return summary, err
+	}
+	err = DB.Raw(`SELECT t.category_id, c.name AS cname, t.priority_id, p.name AS pname, p.level,
+		coalesce(sum(t.total) FILTER (WHERE NOT t.negative), 0) AS income,
+		coalesce(sum(t.total) FILTER (WHERE t.negative), 0) AS expense,
+		coalesce(sum(t.count), 0) AS count,
+		GROUPING(t.category_id, c.name) AS group_category,
+		GROUPING(t.priority_id, p.name, p.level) AS group_priority
+	FROM `+source+` t
+	LEFT JOIN categories c ON c.id = t.category_id
+	LEFT JOIN priorities p ON p.id = t.priority_id
+	GROUP BY GROUPING SETS ((), (t.category_id, c.name), (t.priority_id, p.name, p.level))
+	ORDER BY expense DESC`, args...).Scan(&rows).Error
+	if err != nil {
+		return summary, err
+	}
+	for _, row := range rows {
+		switch {
+		case row.GroupCategory != 0 && row.GroupPriority != 0:
+			summary.Income = row.Income
+			summary.Expense = row.Expense
+			summary.Net = row.Income - row.Expense
+			summary.Count = row.Count
+		case row.GroupCategory == 0 && row.CategoryID != nil && row.Expense > 0 && len(summary.TopCategories) < 3:
+			spending := models.SpendingCategory{CategoryID: *row.CategoryID, Total: row.Expense, Negative: true}
+			if row.Cname != nil {
+				spending.Cname = *row.Cname
+			}
+			summary.TopCategories = append(summary.TopCategories, spending)
+		case row.GroupPriority == 0 && row.PriorityID != nil && row.Expense > 0 && len(summary.TopPriorities) < 3:
+			spending := models.SpendingPriority{PriorityID: *row.PriorityID, Total: row.Expense, Negative: true}
+			if row.Pname != nil {
+				spending.Pname = *row.Pname
+			}
+			if row.Level != nil {
+				spending.Level = *row.Level
+			}
+			summary.TopPriorities = append(summary.TopPriorities, spending)
+		}
+	}
+	largest := []models.Transaction{}
+	err = DB.Preload(clause.Associations).Where("user_id = ? AND created_at BETWEEN ? AND ? AND negative", id, startDate, endDate).Order("amount desc").Limit(1).Find(&largest).Error
+	if len(largest) > 0 {
+		summary.LargestTransaction = &largest[0]
+	}
+	return summary, err
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"os"
+	// the runtime image has no zoneinfo for users' time zones
+	_ "time/tzdata"
 
 	"github.com/lpernett/godotenv"
 	"github.com/peternabil/go-api/controllers"
@@ -33,6 +35,38 @@ func migrate() {
 	if err != nil {
 		fmt.Println(err.Error())
 	}
+	err = intitializers.DB.AutoMigrate(&models.Rule{})
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+	err = intitializers.DB.AutoMigrate(&models.Budget{})
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+	err = intitializers.DB.AutoMigrate(&models.EnvelopeEvent{})
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+	err = intitializers.DB.AutoMigrate(&models.Goal{})
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+	err = intitializers.DB.AutoMigrate(&models.Debt{})
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+	err = intitializers.DB.AutoMigrate(&models.Recurring{})
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+	err = intitializers.DB.AutoMigrate(&models.DailyRollup{})
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+	err = intitializers.DB.AutoMigrate(&models.Report{})
+	if err != nil {
+		fmt.Println(err.Error())
+	}
 }
 
 func main() {
@@ -30,4 +30,36 @@ func main() {
 	if err != nil {
 		fmt.Println(err.Error())
 	}
+	err = intitializers.DB.AutoMigrate(&models.Rule{})
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+	err = intitializers.DB.AutoMigrate(&models.Budget{})
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+	err = intitializers.DB.AutoMigrate(&models.EnvelopeEvent{})
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+	err = intitializers.DB.AutoMigrate(&models.Goal{})
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+	err = intitializers.DB.AutoMigrate(&models.Debt{})
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+	err = intitializers.DB.AutoMigrate(&models.Recurring{})
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+	err = intitializers.DB.AutoMigrate(&models.DailyRollup{})
+	if err != nil {
+		fmt.Println(err.Error())
+	}
+	err = intitializers.DB.AutoMigrate(&models.Report{})
+	if err != nil {
+		fmt.Println(err.Error())
+	}
 }
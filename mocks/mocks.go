@@ -37,6 +37,36 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// CheckRollups mocks base method.
+func (m *MockStore) CheckRollups(id uuid.UUID) ([]models.RollupMismatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckRollups", id)
+	ret0, _ := ret[0].([]models.RollupMismatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckRollups indicates an expected call of CheckRollups.
+func (mr *MockStoreMockRecorder) CheckRollups(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckRollups", reflect.TypeOf((*MockStore)(nil).CheckRollups), id)
+}
+
+// CreateBudget mocks base method.
+func (m *MockStore) CreateBudget(budget *models.Budget) (models.Budget, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBudget", budget)
+	ret0, _ := ret[0].(models.Budget)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBudget indicates an expected call of CreateBudget.
+func (mr *MockStoreMockRecorder) CreateBudget(budget interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBudget", reflect.TypeOf((*MockStore)(nil).CreateBudget), budget)
+}
+
 // CreateCategory mocks base method.
 func (m *MockStore) CreateCategory(category *models.Category) (models.Category, error) {
 	m.ctrl.T.Helper()
@@ -52,6 +82,51 @@ func (mr *MockStoreMockRecorder) CreateCategory(category interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCategory", reflect.TypeOf((*MockStore)(nil).CreateCategory), category)
 }
 
+// CreateDebt mocks base method.
+func (m *MockStore) CreateDebt(debt *models.Debt) (models.Debt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDebt", debt)
+	ret0, _ := ret[0].(models.Debt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDebt indicates an expected call of CreateDebt.
+func (mr *MockStoreMockRecorder) CreateDebt(debt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDebt", reflect.TypeOf((*MockStore)(nil).CreateDebt), debt)
+}
+
+// CreateEnvelopeEvent mocks base method.
+func (m *MockStore) CreateEnvelopeEvent(event *models.EnvelopeEvent) (models.EnvelopeEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEnvelopeEvent", event)
+	ret0, _ := ret[0].(models.EnvelopeEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEnvelopeEvent indicates an expected call of CreateEnvelopeEvent.
+func (mr *MockStoreMockRecorder) CreateEnvelopeEvent(event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEnvelopeEvent", reflect.TypeOf((*MockStore)(nil).CreateEnvelopeEvent), event)
+}
+
+// CreateGoal mocks base method.
+func (m *MockStore) CreateGoal(goal *models.Goal) (models.Goal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGoal", goal)
+	ret0, _ := ret[0].(models.Goal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateGoal indicates an expected call of CreateGoal.
+func (mr *MockStoreMockRecorder) CreateGoal(goal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGoal", reflect.TypeOf((*MockStore)(nil).CreateGoal), goal)
+}
+
 // CreatePriority mocks base method.
 func (m *MockStore) CreatePriority(priority *models.Priority) (models.Priority, error) {
 	m.ctrl.T.Helper()
@@ -67,6 +142,51 @@ func (mr *MockStoreMockRecorder) CreatePriority(priority interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePriority", reflect.TypeOf((*MockStore)(nil).CreatePriority), priority)
 }
 
+// CreateRecurring mocks base method.
+func (m *MockStore) CreateRecurring(recurring *models.Recurring) (models.Recurring, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRecurring", recurring)
+	ret0, _ := ret[0].(models.Recurring)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRecurring indicates an expected call of CreateRecurring.
+func (mr *MockStoreMockRecorder) CreateRecurring(recurring interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecurring", reflect.TypeOf((*MockStore)(nil).CreateRecurring), recurring)
+}
+
+// CreateReport mocks base method.
+func (m *MockStore) CreateReport(report *models.Report) (models.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReport", report)
+	ret0, _ := ret[0].(models.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReport indicates an expected call of CreateReport.
+func (mr *MockStoreMockRecorder) CreateReport(report interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReport", reflect.TypeOf((*MockStore)(nil).CreateReport), report)
+}
+
+// CreateRule mocks base method.
+func (m *MockStore) CreateRule(rule *models.Rule) (models.Rule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRule", rule)
+	ret0, _ := ret[0].(models.Rule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRule indicates an expected call of CreateRule.
+func (mr *MockStoreMockRecorder) CreateRule(rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRule", reflect.TypeOf((*MockStore)(nil).CreateRule), rule)
+}
+
 // CreateToken mocks base method.
 func (m *MockStore) CreateToken(user models.User) (string, error) {
 	m.ctrl.T.Helper()
@@ -97,6 +217,20 @@ func (mr *MockStoreMockRecorder) CreateTransaction(transaction interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransaction", reflect.TypeOf((*MockStore)(nil).CreateTransaction), transaction)
 }
 
+// DeleteBudget mocks base method.
+func (m *MockStore) DeleteBudget(budget *models.Budget) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBudget", budget)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBudget indicates an expected call of DeleteBudget.
+func (mr *MockStoreMockRecorder) DeleteBudget(budget interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBudget", reflect.TypeOf((*MockStore)(nil).DeleteBudget), budget)
+}
+
 // DeleteCategory mocks base method.
 func (m *MockStore) DeleteCategory(category *models.Category) error {
 	m.ctrl.T.Helper()
@@ -111,6 +245,34 @@ func (mr *MockStoreMockRecorder) DeleteCategory(category interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategory", reflect.TypeOf((*MockStore)(nil).DeleteCategory), category)
 }
 
+// DeleteDebt mocks base method.
+func (m *MockStore) DeleteDebt(debt *models.Debt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDebt", debt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDebt indicates an expected call of DeleteDebt.
+func (mr *MockStoreMockRecorder) DeleteDebt(debt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDebt", reflect.TypeOf((*MockStore)(nil).DeleteDebt), debt)
+}
+
+// DeleteGoal mocks base method.
+func (m *MockStore) DeleteGoal(goal *models.Goal) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGoal", goal)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGoal indicates an expected call of DeleteGoal.
+func (mr *MockStoreMockRecorder) DeleteGoal(goal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGoal", reflect.TypeOf((*MockStore)(nil).DeleteGoal), goal)
+}
+
 // DeletePriority mocks base method.
 func (m *MockStore) DeletePriority(priority *models.Priority) error {
 	m.ctrl.T.Helper()
@@ -125,6 +287,48 @@ func (mr *MockStoreMockRecorder) DeletePriority(priority interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePriority", reflect.TypeOf((*MockStore)(nil).DeletePriority), priority)
 }
 
+// DeleteRecurring mocks base method.
+func (m *MockStore) DeleteRecurring(recurring *models.Recurring) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRecurring", recurring)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRecurring indicates an expected call of DeleteRecurring.
+func (mr *MockStoreMockRecorder) DeleteRecurring(recurring interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecurring", reflect.TypeOf((*MockStore)(nil).DeleteRecurring), recurring)
+}
+
+// DeleteReport mocks base method.
+func (m *MockStore) DeleteReport(report *models.Report) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReport", report)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReport indicates an expected call of DeleteReport.
+func (mr *MockStoreMockRecorder) DeleteReport(report interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReport", reflect.TypeOf((*MockStore)(nil).DeleteReport), report)
+}
+
+// DeleteRule mocks base method.
+func (m *MockStore) DeleteRule(rule *models.Rule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRule", rule)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRule indicates an expected call of DeleteRule.
+func (mr *MockStoreMockRecorder) DeleteRule(rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRule", reflect.TypeOf((*MockStore)(nil).DeleteRule), rule)
+}
+
 // DeleteTransaction mocks base method.
 func (m *MockStore) DeleteTransaction(transaction *models.Transaction) error {
 	m.ctrl.T.Helper()
@@ -139,6 +343,21 @@ func (mr *MockStoreMockRecorder) DeleteTransaction(transaction interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTransaction", reflect.TypeOf((*MockStore)(nil).DeleteTransaction), transaction)
 }
 
+// EditBudget mocks base method.
+func (m *MockStore) EditBudget(budget *models.Budget) (models.Budget, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditBudget", budget)
+	ret0, _ := ret[0].(models.Budget)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EditBudget indicates an expected call of EditBudget.
+func (mr *MockStoreMockRecorder) EditBudget(budget interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditBudget", reflect.TypeOf((*MockStore)(nil).EditBudget), budget)
+}
+
 // EditCategory mocks base method.
 func (m *MockStore) EditCategory(category *models.Category) (models.Category, error) {
 	m.ctrl.T.Helper()
@@ -154,6 +373,36 @@ func (mr *MockStoreMockRecorder) EditCategory(category interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditCategory", reflect.TypeOf((*MockStore)(nil).EditCategory), category)
 }
 
+// EditDebt mocks base method.
+func (m *MockStore) EditDebt(debt *models.Debt) (models.Debt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditDebt", debt)
+	ret0, _ := ret[0].(models.Debt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EditDebt indicates an expected call of EditDebt.
+func (mr *MockStoreMockRecorder) EditDebt(debt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditDebt", reflect.TypeOf((*MockStore)(nil).EditDebt), debt)
+}
+
+// EditGoal mocks base method.
+func (m *MockStore) EditGoal(goal *models.Goal) (models.Goal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditGoal", goal)
+	ret0, _ := ret[0].(models.Goal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EditGoal indicates an expected call of EditGoal.
+func (mr *MockStoreMockRecorder) EditGoal(goal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditGoal", reflect.TypeOf((*MockStore)(nil).EditGoal), goal)
+}
+
 // EditPriority mocks base method.
 func (m *MockStore) EditPriority(priority *models.Priority) (models.Priority, error) {
 	m.ctrl.T.Helper()
@@ -169,6 +418,36 @@ func (mr *MockStoreMockRecorder) EditPriority(priority interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditPriority", reflect.TypeOf((*MockStore)(nil).EditPriority), priority)
 }
 
+// EditReport mocks base method.
+func (m *MockStore) EditReport(report *models.Report) (models.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditReport", report)
+	ret0, _ := ret[0].(models.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EditReport indicates an expected call of EditReport.
+func (mr *MockStoreMockRecorder) EditReport(report interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditReport", reflect.TypeOf((*MockStore)(nil).EditReport), report)
+}
+
+// EditRule mocks base method.
+func (m *MockStore) EditRule(rule *models.Rule) (models.Rule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditRule", rule)
+	ret0, _ := ret[0].(models.Rule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EditRule indicates an expected call of EditRule.
+func (mr *MockStoreMockRecorder) EditRule(rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditRule", reflect.TypeOf((*MockStore)(nil).EditRule), rule)
+}
+
 // EditTransaction mocks base method.
 func (m *MockStore) EditTransaction(transaction *models.Transaction) (models.Transaction, error) {
 	m.ctrl.T.Helper()
@@ -184,6 +463,35 @@ func (mr *MockStoreMockRecorder) EditTransaction(transaction interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditTransaction", reflect.TypeOf((*MockStore)(nil).EditTransaction), transaction)
 }
 
+// EditTransactions mocks base method.
+func (m *MockStore) EditTransactions(transactions []models.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditTransactions", transactions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EditTransactions indicates an expected call of EditTransactions.
+func (mr *MockStoreMockRecorder) EditTransactions(transactions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditTransactions", reflect.TypeOf((*MockStore)(nil).EditTransactions), transactions)
+}
+
+// EditUser mocks base method.
+func (m *MockStore) EditUser(user *models.User) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditUser", user)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EditUser indicates an expected call of EditUser.
+func (mr *MockStoreMockRecorder) EditUser(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditUser", reflect.TypeOf((*MockStore)(nil).EditUser), user)
+}
+
 // FindUser mocks base method.
 func (m *MockStore) FindUser(email string) (models.User, error) {
 	m.ctrl.T.Helper()
@@ -199,6 +507,66 @@ func (mr *MockStoreMockRecorder) FindUser(email interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUser", reflect.TypeOf((*MockStore)(nil).FindUser), email)
 }
 
+// GetAllTransactions mocks base method.
+func (m *MockStore) GetAllTransactions(id uuid.UUID) ([]models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllTransactions", id)
+	ret0, _ := ret[0].([]models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllTransactions indicates an expected call of GetAllTransactions.
+func (mr *MockStoreMockRecorder) GetAllTransactions(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTransactions", reflect.TypeOf((*MockStore)(nil).GetAllTransactions), id)
+}
+
+// GetBalance mocks base method.
+func (m *MockStore) GetBalance(id uuid.UUID, date time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalance", id, date)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalance indicates an expected call of GetBalance.
+func (mr *MockStoreMockRecorder) GetBalance(id, date interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalance", reflect.TypeOf((*MockStore)(nil).GetBalance), id, date)
+}
+
+// GetBudget mocks base method.
+func (m *MockStore) GetBudget(id uuid.UUID, budget *models.Budget) (models.Budget, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBudget", id, budget)
+	ret0, _ := ret[0].(models.Budget)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBudget indicates an expected call of GetBudget.
+func (mr *MockStoreMockRecorder) GetBudget(id, budget interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBudget", reflect.TypeOf((*MockStore)(nil).GetBudget), id, budget)
+}
+
+// GetBudgets mocks base method.
+func (m *MockStore) GetBudgets(id uuid.UUID) ([]models.Budget, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBudgets", id)
+	ret0, _ := ret[0].([]models.Budget)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBudgets indicates an expected call of GetBudgets.
+func (mr *MockStoreMockRecorder) GetBudgets(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBudgets", reflect.TypeOf((*MockStore)(nil).GetBudgets), id)
+}
+
 // GetCategories mocks base method.
 func (m *MockStore) GetCategories(id uuid.UUID) ([]models.Category, error) {
 	m.ctrl.T.Helper()
@@ -229,19 +597,124 @@ func (mr *MockStoreMockRecorder) GetCategory(id, category interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategory", reflect.TypeOf((*MockStore)(nil).GetCategory), id, category)
 }
 
+// GetDebt mocks base method.
+func (m *MockStore) GetDebt(id uuid.UUID, debt *models.Debt) (models.Debt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDebt", id, debt)
+	ret0, _ := ret[0].(models.Debt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDebt indicates an expected call of GetDebt.
+func (mr *MockStoreMockRecorder) GetDebt(id, debt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDebt", reflect.TypeOf((*MockStore)(nil).GetDebt), id, debt)
+}
+
+// GetDebtPayments mocks base method.
+func (m *MockStore) GetDebtPayments(id uuid.UUID) (map[uuid.UUID]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDebtPayments", id)
+	ret0, _ := ret[0].(map[uuid.UUID]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDebtPayments indicates an expected call of GetDebtPayments.
+func (mr *MockStoreMockRecorder) GetDebtPayments(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDebtPayments", reflect.TypeOf((*MockStore)(nil).GetDebtPayments), id)
+}
+
+// GetDebts mocks base method.
+func (m *MockStore) GetDebts(id uuid.UUID) ([]models.Debt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDebts", id)
+	ret0, _ := ret[0].([]models.Debt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDebts indicates an expected call of GetDebts.
+func (mr *MockStoreMockRecorder) GetDebts(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDebts", reflect.TypeOf((*MockStore)(nil).GetDebts), id)
+}
+
+// GetEnvelopeEvents mocks base method.
+func (m *MockStore) GetEnvelopeEvents(id uuid.UUID, startDate, endDate time.Time) ([]models.EnvelopeEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEnvelopeEvents", id, startDate, endDate)
+	ret0, _ := ret[0].([]models.EnvelopeEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEnvelopeEvents indicates an expected call of GetEnvelopeEvents.
+func (mr *MockStoreMockRecorder) GetEnvelopeEvents(id, startDate, endDate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEnvelopeEvents", reflect.TypeOf((*MockStore)(nil).GetEnvelopeEvents), id, startDate, endDate)
+}
+
+// GetGoal mocks base method.
+func (m *MockStore) GetGoal(id uuid.UUID, goal *models.Goal) (models.Goal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGoal", id, goal)
+	ret0, _ := ret[0].(models.Goal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGoal indicates an expected call of GetGoal.
+func (mr *MockStoreMockRecorder) GetGoal(id, goal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGoal", reflect.TypeOf((*MockStore)(nil).GetGoal), id, goal)
+}
+
+// GetGoalTransactions mocks base method.
+func (m *MockStore) GetGoalTransactions(id uuid.UUID, goal *models.Goal) ([]models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGoalTransactions", id, goal)
+	ret0, _ := ret[0].([]models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGoalTransactions indicates an expected call of GetGoalTransactions.
+func (mr *MockStoreMockRecorder) GetGoalTransactions(id, goal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGoalTransactions", reflect.TypeOf((*MockStore)(nil).GetGoalTransactions), id, goal)
+}
+
+// GetGoals mocks base method.
+func (m *MockStore) GetGoals(id uuid.UUID) ([]models.Goal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGoals", id)
+	ret0, _ := ret[0].([]models.Goal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGoals indicates an expected call of GetGoals.
+func (mr *MockStoreMockRecorder) GetGoals(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGoals", reflect.TypeOf((*MockStore)(nil).GetGoals), id)
+}
+
 // GetHighestSpendingCategory mocks base method.
-func (m *MockStore) GetHighestSpendingCategory(id uuid.UUID, startDate, endDate time.Time, negative bool) ([]models.SpendingCategory, error) {
+func (m *MockStore) GetHighestSpendingCategory(id uuid.UUID, startDate, endDate time.Time, negative bool, depth int) ([]models.SpendingCategory, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetHighestSpendingCategory", id, startDate, endDate, negative)
+	ret := m.ctrl.Call(m, "GetHighestSpendingCategory", id, startDate, endDate, negative, depth)
 	ret0, _ := ret[0].([]models.SpendingCategory)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetHighestSpendingCategory indicates an expected call of GetHighestSpendingCategory.
-func (mr *MockStoreMockRecorder) GetHighestSpendingCategory(id, startDate, endDate, negative interface{}) *gomock.Call {
+func (mr *MockStoreMockRecorder) GetHighestSpendingCategory(id, startDate, endDate, negative, depth interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHighestSpendingCategory", reflect.TypeOf((*MockStore)(nil).GetHighestSpendingCategory), id, startDate, endDate, negative)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHighestSpendingCategory", reflect.TypeOf((*MockStore)(nil).GetHighestSpendingCategory), id, startDate, endDate, negative, depth)
 }
 
 // GetHighestSpendingPriority mocks base method.
@@ -259,6 +732,36 @@ func (mr *MockStoreMockRecorder) GetHighestSpendingPriority(id, startDate, endDa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHighestSpendingPriority", reflect.TypeOf((*MockStore)(nil).GetHighestSpendingPriority), id, startDate, endDate, negative)
 }
 
+// GetMonthlySpendingCategory mocks base method.
+func (m *MockStore) GetMonthlySpendingCategory(id uuid.UUID, startDate, endDate time.Time, negative bool) ([]models.SpendingCategory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMonthlySpendingCategory", id, startDate, endDate, negative)
+	ret0, _ := ret[0].([]models.SpendingCategory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMonthlySpendingCategory indicates an expected call of GetMonthlySpendingCategory.
+func (mr *MockStoreMockRecorder) GetMonthlySpendingCategory(id, startDate, endDate, negative interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMonthlySpendingCategory", reflect.TypeOf((*MockStore)(nil).GetMonthlySpendingCategory), id, startDate, endDate, negative)
+}
+
+// GetMonthlySpendingLevel mocks base method.
+func (m *MockStore) GetMonthlySpendingLevel(id uuid.UUID, startDate, endDate time.Time) ([]models.SpendingPriority, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMonthlySpendingLevel", id, startDate, endDate)
+	ret0, _ := ret[0].([]models.SpendingPriority)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMonthlySpendingLevel indicates an expected call of GetMonthlySpendingLevel.
+func (mr *MockStoreMockRecorder) GetMonthlySpendingLevel(id, startDate, endDate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMonthlySpendingLevel", reflect.TypeOf((*MockStore)(nil).GetMonthlySpendingLevel), id, startDate, endDate)
+}
+
 // GetPriorities mocks base method.
 func (m *MockStore) GetPriorities(id uuid.UUID) ([]models.Priority, error) {
 	m.ctrl.T.Helper()
@@ -289,6 +792,156 @@ func (mr *MockStoreMockRecorder) GetPriority(id, priority interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPriority", reflect.TypeOf((*MockStore)(nil).GetPriority), id, priority)
 }
 
+// GetRecentTransactions mocks base method.
+func (m *MockStore) GetRecentTransactions(id uuid.UUID, since time.Time, limit int) ([]models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecentTransactions", id, since, limit)
+	ret0, _ := ret[0].([]models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecentTransactions indicates an expected call of GetRecentTransactions.
+func (mr *MockStoreMockRecorder) GetRecentTransactions(id, since, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecentTransactions", reflect.TypeOf((*MockStore)(nil).GetRecentTransactions), id, since, limit)
+}
+
+// GetRecurring mocks base method.
+func (m *MockStore) GetRecurring(id uuid.UUID, recurring *models.Recurring) (models.Recurring, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecurring", id, recurring)
+	ret0, _ := ret[0].(models.Recurring)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecurring indicates an expected call of GetRecurring.
+func (mr *MockStoreMockRecorder) GetRecurring(id, recurring interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecurring", reflect.TypeOf((*MockStore)(nil).GetRecurring), id, recurring)
+}
+
+// GetRecurrings mocks base method.
+func (m *MockStore) GetRecurrings(id uuid.UUID) ([]models.Recurring, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecurrings", id)
+	ret0, _ := ret[0].([]models.Recurring)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecurrings indicates an expected call of GetRecurrings.
+func (mr *MockStoreMockRecorder) GetRecurrings(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecurrings", reflect.TypeOf((*MockStore)(nil).GetRecurrings), id)
+}
+
+// GetReport mocks base method.
+func (m *MockStore) GetReport(id uuid.UUID, report *models.Report) (models.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReport", id, report)
+	ret0, _ := ret[0].(models.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReport indicates an expected call of GetReport.
+func (mr *MockStoreMockRecorder) GetReport(id, report interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReport", reflect.TypeOf((*MockStore)(nil).GetReport), id, report)
+}
+
+// GetReports mocks base method.
+func (m *MockStore) GetReports(id uuid.UUID) ([]models.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReports", id)
+	ret0, _ := ret[0].([]models.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReports indicates an expected call of GetReports.
+func (mr *MockStoreMockRecorder) GetReports(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReports", reflect.TypeOf((*MockStore)(nil).GetReports), id)
+}
+
+// GetRule mocks base method.
+func (m *MockStore) GetRule(id uuid.UUID, rule *models.Rule) (models.Rule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRule", id, rule)
+	ret0, _ := ret[0].(models.Rule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRule indicates an expected call of GetRule.
+func (mr *MockStoreMockRecorder) GetRule(id, rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRule", reflect.TypeOf((*MockStore)(nil).GetRule), id, rule)
+}
+
+// GetRules mocks base method.
+func (m *MockStore) GetRules(id uuid.UUID) ([]models.Rule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRules", id)
+	ret0, _ := ret[0].([]models.Rule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRules indicates an expected call of GetRules.
+func (mr *MockStoreMockRecorder) GetRules(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRules", reflect.TypeOf((*MockStore)(nil).GetRules), id)
+}
+
+// GetSpendingPatterns mocks base method.
+func (m *MockStore) GetSpendingPatterns(id uuid.UUID, startDate, endDate time.Time, negative bool, categoryID, priorityID *uuid.UUID) ([]models.SpendingPattern, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSpendingPatterns", id, startDate, endDate, negative, categoryID, priorityID)
+	ret0, _ := ret[0].([]models.SpendingPattern)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSpendingPatterns indicates an expected call of GetSpendingPatterns.
+func (mr *MockStoreMockRecorder) GetSpendingPatterns(id, startDate, endDate, negative, categoryID, priorityID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpendingPatterns", reflect.TypeOf((*MockStore)(nil).GetSpendingPatterns), id, startDate, endDate, negative, categoryID, priorityID)
+}
+
+// GetSpendingPivot mocks base method.
+func (m *MockStore) GetSpendingPivot(id uuid.UUID, startDate, endDate time.Time, negative bool, rows, unit string) ([]models.PivotCell, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSpendingPivot", id, startDate, endDate, negative, rows, unit)
+	ret0, _ := ret[0].([]models.PivotCell)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSpendingPivot indicates an expected call of GetSpendingPivot.
+func (mr *MockStoreMockRecorder) GetSpendingPivot(id, startDate, endDate, negative, rows, unit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpendingPivot", reflect.TypeOf((*MockStore)(nil).GetSpendingPivot), id, startDate, endDate, negative, rows, unit)
+}
+
+// GetSpendingStats mocks base method.
+func (m *MockStore) GetSpendingStats(id uuid.UUID, startDate, endDate time.Time, negative bool, rows string) ([]models.SpendingStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSpendingStats", id, startDate, endDate, negative, rows)
+	ret0, _ := ret[0].([]models.SpendingStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSpendingStats indicates an expected call of GetSpendingStats.
+func (mr *MockStoreMockRecorder) GetSpendingStats(id, startDate, endDate, negative, rows interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpendingStats", reflect.TypeOf((*MockStore)(nil).GetSpendingStats), id, startDate, endDate, negative, rows)
+}
+
 // GetTransaction mocks base method.
 func (m *MockStore) GetTransaction(transaction *models.Transaction) (models.Transaction, error) {
 	m.ctrl.T.Helper()
@@ -319,6 +972,21 @@ func (mr *MockStoreMockRecorder) GetTransactions(id, page, pageSize, count inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactions", reflect.TypeOf((*MockStore)(nil).GetTransactions), id, page, pageSize, count)
 }
 
+// GetTransactionsDateRange mocks base method.
+func (m *MockStore) GetTransactionsDateRange(id uuid.UUID, startDate, endDate time.Time) ([]models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionsDateRange", id, startDate, endDate)
+	ret0, _ := ret[0].([]models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionsDateRange indicates an expected call of GetTransactionsDateRange.
+func (mr *MockStoreMockRecorder) GetTransactionsDateRange(id, startDate, endDate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsDateRange", reflect.TypeOf((*MockStore)(nil).GetTransactionsDateRange), id, startDate, endDate)
+}
+
 // GetTransactionsDateRangeGroupByDay mocks base method.
 func (m *MockStore) GetTransactionsDateRangeGroupByDay(id uuid.UUID, startDate, endDate time.Time, negative bool) ([]models.Spending, error) {
 	m.ctrl.T.Helper()
@@ -378,6 +1046,36 @@ func (mr *MockStoreMockRecorder) GetUsers() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockStore)(nil).GetUsers))
 }
 
+// MergeCategories mocks base method.
+func (m *MockStore) MergeCategories(id uuid.UUID, source, target *models.Category) (models.MergeResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeCategories", id, source, target)
+	ret0, _ := ret[0].(models.MergeResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeCategories indicates an expected call of MergeCategories.
+func (mr *MockStoreMockRecorder) MergeCategories(id, source, target interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeCategories", reflect.TypeOf((*MockStore)(nil).MergeCategories), id, source, target)
+}
+
+// MergePriorities mocks base method.
+func (m *MockStore) MergePriorities(id uuid.UUID, source, target *models.Priority) (models.MergeResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergePriorities", id, source, target)
+	ret0, _ := ret[0].(models.MergeResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergePriorities indicates an expected call of MergePriorities.
+func (mr *MockStoreMockRecorder) MergePriorities(id, source, target interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergePriorities", reflect.TypeOf((*MockStore)(nil).MergePriorities), id, source, target)
+}
+
 // ReadToken mocks base method.
 func (m *MockStore) ReadToken(tokenStr string) (models.User, error) {
 	m.ctrl.T.Helper()
@@ -393,32 +1091,75 @@ func (mr *MockStoreMockRecorder) ReadToken(tokenStr interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadToken", reflect.TypeOf((*MockStore)(nil).ReadToken), tokenStr)
 }
 
+// RebuildRollups mocks base method.
+func (m *MockStore) RebuildRollups(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RebuildRollups", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RebuildRollups indicates an expected call of RebuildRollups.
+func (mr *MockStoreMockRecorder) RebuildRollups(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebuildRollups", reflect.TypeOf((*MockStore)(nil).RebuildRollups), id)
+}
+
+// RunReport mocks base method.
+func (m *MockStore) RunReport(id uuid.UUID, report models.Report, startDate, endDate time.Time) ([]map[string]any, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunReport", id, report, startDate, endDate)
+	ret0, _ := ret[0].([]map[string]any)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunReport indicates an expected call of RunReport.
+func (mr *MockStoreMockRecorder) RunReport(id, report, startDate, endDate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunReport", reflect.TypeOf((*MockStore)(nil).RunReport), id, report, startDate, endDate)
+}
+
+// SeedCategoriesAndPriorities mocks base method.
+func (m *MockStore) SeedCategoriesAndPriorities(categories []models.Category, priorities []models.Priority) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SeedCategoriesAndPriorities", categories, priorities)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SeedCategoriesAndPriorities indicates an expected call of SeedCategoriesAndPriorities.
+func (mr *MockStoreMockRecorder) SeedCategoriesAndPriorities(categories, priorities interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SeedCategoriesAndPriorities", reflect.TypeOf((*MockStore)(nil).SeedCategoriesAndPriorities), categories, priorities)
+}
+
 // SignUp mocks base method.
-func (m *MockStore) SignUp(user *models.User) (models.User, error) {
+func (m *MockStore) SignUp(user *models.User, categories []models.Category, priorities []models.Priority) (models.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SignUp", user)
+	ret := m.ctrl.Call(m, "SignUp", user, categories, priorities)
 	ret0, _ := ret[0].(models.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SignUp indicates an expected call of SignUp.
-func (mr *MockStoreMockRecorder) SignUp(user interface{}) *gomock.Call {
+func (mr *MockStoreMockRecorder) SignUp(user, categories, priorities interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignUp", reflect.TypeOf((*MockStore)(nil).SignUp), user)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignUp", reflect.TypeOf((*MockStore)(nil).SignUp), user, categories, priorities)
 }
 
 // TotalSpending mocks base method.
-func (m *MockStore) TotalSpending(id uuid.UUID, startDate, endDate time.Time, negative bool) ([]models.SpendingPriority, error) {
+func (m *MockStore) TotalSpending(id uuid.UUID, startDate, endDate time.Time) (models.Summary, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "TotalSpending", id, startDate, endDate, negative)
-	ret0, _ := ret[0].([]models.SpendingPriority)
+	ret := m.ctrl.Call(m, "TotalSpending", id, startDate, endDate)
+	ret0, _ := ret[0].(models.Summary)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // TotalSpending indicates an expected call of TotalSpending.
-func (mr *MockStoreMockRecorder) TotalSpending(id, startDate, endDate, negative interface{}) *gomock.Call {
+func (mr *MockStoreMockRecorder) TotalSpending(id, startDate, endDate interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TotalSpending", reflect.TypeOf((*MockStore)(nil).TotalSpending), id, startDate, endDate, negative)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TotalSpending", reflect.TypeOf((*MockStore)(nil).TotalSpending), id, startDate, endDate)
 }
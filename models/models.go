@@ -15,6 +15,12 @@ type User struct {
 	FirstName string
 	LastName  string
 	Password  string
+	// TimeZone is the IANA name of the time zone analytics are reported in;
+	// empty means UTC.
+	TimeZone string
+	// FiscalMonthStart is the day months start on for the month presets,
+	// e.g. 25 for payday; 0 means the 1st.
+	FiscalMonthStart int
 }
 
 type Category struct {
@@ -22,6 +28,7 @@ type Category struct {
 	ID          uuid.UUID `gorm:"type:uuid;default:gen_random_uuid()"`
 	Name        string
 	Description string
+	ParentID    *uuid.UUID `gorm:"type:uuid"`
 	UserID      uuid.UUID
 }
 
@@ -45,9 +52,164 @@ type Transaction struct {
 	Negative    bool
 	Description string
 	PriorityID  uuid.UUID
+	Tags        string
+	GoalID      *uuid.UUID `gorm:"type:uuid"`
+	DebtID      *uuid.UUID `gorm:"type:uuid"`
 	UserID      uuid.UUID
 }
 
+// Rule sets fields on transactions that match all of its conditions.
+// Conditions left empty (or nil) always match, and rules run in Position order.
+type Rule struct {
+	gorm.Model
+	ID                 uuid.UUID `gorm:"type:uuid;default:gen_random_uuid()"`
+	Name               string
+	Position           int
+	TitlePattern       string
+	DescriptionPattern string
+	MinAmount          *int
+	MaxAmount          *int
+	Negative           *bool
+	Weekday            *time.Weekday
+	SetCategoryID      *uuid.UUID `gorm:"type:uuid"`
+	SetPriorityID      *uuid.UUID `gorm:"type:uuid"`
+	SetTags            string
+	SetTitle           string
+	StopProcessing     bool
+	UserID             uuid.UUID
+}
+
+// Budget caps spending on either a category or a priority for each period.
+// Period is "monthly", "weekly" or "custom"; custom budgets run from
+// StartDate to EndDate.
+type Budget struct {
+	gorm.Model
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid()"`
+	Name       string
+	CategoryID *uuid.UUID `gorm:"type:uuid"`
+	PriorityID *uuid.UUID `gorm:"type:uuid"`
+	Amount     int
+	Period     string
+	StartDate  *time.Time
+	EndDate    *time.Time
+	UserID     uuid.UUID
+}
+
+// Goal is a savings target. Contributions are transactions linked to the goal
+// through their GoalID or, when CategoryID is set, filed under that category.
+type Goal struct {
+	gorm.Model
+	ID           uuid.UUID `gorm:"type:uuid;default:gen_random_uuid()"`
+	Name         string
+	TargetAmount int
+	TargetDate   time.Time
+	CategoryID   *uuid.UUID `gorm:"type:uuid"`
+	UserID       uuid.UUID
+}
+
+// Debt is a loan or card being paid off. APR is a yearly percentage and
+// payments are expense transactions linked through their DebtID.
+type Debt struct {
+	gorm.Model
+	ID             uuid.UUID `gorm:"type:uuid;default:gen_random_uuid()"`
+	Name           string
+	Principal      int
+	APR            float64
+	MinimumPayment int
+	DueDay         *int
+	UserID         uuid.UUID
+}
+
+// EnvelopeEvent records one change to the money assigned to category
+// envelopes for Month (the first day of the month). An "assign" event adds
+// Amount (which may be negative) to CategoryID; a "move" event takes Amount
+// from FromCategoryID and gives it to CategoryID. A "merge" event is a move
+// recorded when FromCategoryID was merged into CategoryID.
+type EnvelopeEvent struct {
+	gorm.Model
+	ID             uuid.UUID `gorm:"type:uuid;default:gen_random_uuid()"`
+	Kind           string
+	Month          time.Time
+	CategoryID     uuid.UUID  `gorm:"type:uuid"`
+	FromCategoryID *uuid.UUID `gorm:"type:uuid"`
+	Amount         int
+	Note           string
+	UserID         uuid.UUID
+}
+
+// Recurring is a recurring charge the user has confirmed or dismissed after
+// it was detected in their history. Pattern is the normalized title it was
+// detected under, Cadence one of weekly, monthly or yearly and LastDate the
+// latest charge it was detected from.
+type Recurring struct {
+	gorm.Model
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid()"`
+	Pattern    string
+	Title      string
+	Status     string
+	Cadence    string
+	Amount     int
+	LastDate   time.Time
+	NextDate   time.Time
+	CategoryID uuid.UUID `gorm:"type:uuid"`
+	UserID     uuid.UUID
+}
+
+// Report is a saved custom report. Dimensions and Measures are comma
+// separated names known to the reports package; filters left empty (or nil)
+// match every transaction.
+type Report struct {
+	gorm.Model
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid()"`
+	Name       string
+	Dimensions string
+	Measures   string
+	CategoryID *uuid.UUID `gorm:"type:uuid"`
+	PriorityID *uuid.UUID `gorm:"type:uuid"`
+	Tag        string
+	Negative   *bool
+	MinAmount  *int
+	MaxAmount  *int
+	UserID     uuid.UUID
+}
+
+// DailyRollup is the pre-aggregated total and count of a user's transactions
+// per day, category, priority and sign. Days are taken in the user's time
+// zone.
+type DailyRollup struct {
+	UserID     uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Day        time.Time `gorm:"type:date;primaryKey"`
+	CategoryID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	PriorityID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Negative   bool      `gorm:"primaryKey"`
+	Total      int64
+	Count      int64
+}
+
+// RollupMismatch is a rollup that disagrees with the transactions it sums.
+type RollupMismatch struct {
+	Day         time.Time
+	CategoryID  uuid.UUID
+	PriorityID  uuid.UUID
+	Negative    bool
+	RawTotal    int64
+	RollupTotal int64
+	RawCount    int64
+	RollupCount int64
+}
+
+// MergeResult counts the records moved from a merged category or priority.
+type MergeResult struct {
+	Transactions int64
+	Rules        int64
+	Budgets      int64
+	Envelopes    int64
+	Recurring    int64
+	Goals        int64
+	Reports      int64
+	Categories   int64
+}
+
 type Claims struct {
 	Email string `json:"email"`
 	jwt.RegisteredClaims
@@ -75,3 +237,48 @@ type Spending struct {
 	Total    int64
 	Negative bool
 }
+
+// SpendingPattern sums transactions made on one day of the week (0 being
+// Sunday) during one hour of the day.
+type SpendingPattern struct {
+	Weekday int
+	Hour    int
+	Total   int64
+	Count   int64
+}
+
+// SpendingStats describes the amounts of the transactions of one category or
+// priority.
+type SpendingStats struct {
+	ID     uuid.UUID
+	Name   string
+	Count  int64
+	Total  int64
+	Mean   float64
+	Median float64
+	P90    float64
+	Min    int64
+	Max    int64
+}
+
+// PivotCell is the total of one category or priority in one time bucket.
+type PivotCell struct {
+	Date  time.Time
+	ID    uuid.UUID
+	Name  string
+	Total int64
+}
+
+// Summary is the dashboard overview of a period. The top lists rank
+// categories and priorities by expense and LargestTransaction is the biggest
+// single expense.
+type Summary struct {
+	Income             int64
+	Expense            int64
+	Net                int64
+	Count              int64
+	AverageDailySpend  float64
+	TopCategories      []SpendingCategory
+	TopPriorities      []SpendingPriority
+	LargestTransaction *Transaction
+}
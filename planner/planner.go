@@ -0,0 +1,182 @@
+package planner
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	Snowball  = "snowball"
+	Avalanche = "avalanche"
+
+	// maxMonths caps the simulation at fifty years.
+	maxMonths = 600
+)
+
+var (
+	ErrBudgetTooLow = errors.New("the budget does not cover the minimum payments")
+	ErrNeverPaidOff = errors.New("the budget never pays off the debts")
+)
+
+// Debt is the state of a single debt at the start of the plan.
+type Debt struct {
+	ID             uuid.UUID
+	Name           string
+	Balance        int64
+	APR            float64
+	MinimumPayment int64
+	DueDay         *int
+}
+
+// Payment is what happens to one debt in one month of the schedule.
+type Payment struct {
+	DebtID   uuid.UUID
+	Date     time.Time
+	Interest int64
+	Payment  int64
+	Balance  int64
+}
+
+// Month groups the payments made in one month of the schedule.
+type Month struct {
+	Month    time.Time
+	Payments []Payment
+}
+
+// Payoff is when a debt is cleared and how much interest it cost.
+type Payoff struct {
+	DebtID     uuid.UUID
+	Name       string
+	PayoffDate time.Time
+	Interest   int64
+}
+
+// Plan is the outcome of paying the debts off with one strategy.
+type Plan struct {
+	Strategy      string
+	Months        int
+	PayoffDate    time.Time
+	TotalInterest int64
+	TotalPaid     int64
+	Payoffs       []Payoff
+	Schedule      []Month
+}
+
+// order returns the debts in the order the strategy targets them: smallest
+// balance first for the snowball, highest APR first for the avalanche.
+func order(debts []Debt, strategy string) []Debt {
+	ordered := append([]Debt{}, debts...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if strategy == Avalanche && ordered[i].APR != ordered[j].APR {
+			return ordered[i].APR > ordered[j].APR
+		}
+		if ordered[i].Balance != ordered[j].Balance {
+			return ordered[i].Balance < ordered[j].Balance
+		}
+		return ordered[i].APR > ordered[j].APR
+	})
+	return ordered
+}
+
+// paymentDate is the debt's due day in the month starting at month, clamped
+// to the length of the month, or the first of the month without a due day.
+func paymentDate(debt Debt, month time.Time) time.Time {
+	if debt.DueDay == nil {
+		return month
+	}
+	last := month.AddDate(0, 1, -1).Day()
+	day := *debt.DueDay
+	if day > last {
+		day = last
+	}
+	return month.AddDate(0, 0, day-1)
+}
+
+// Simulate pays budget towards the debts every month starting with the
+// month of start. Each month interest is added to every balance, all
+// minimum payments are made, and whatever is left of the budget goes to the
+// debt the strategy targets first.
+func Simulate(debts []Debt, budget int64, strategy string, start time.Time) (Plan, error) {
+	plan := Plan{Strategy: strategy, Payoffs: []Payoff{}, Schedule: []Month{}}
+	var minimums int64
+	for _, d := range debts {
+		if d.Balance > 0 {
+			minimums += d.MinimumPayment
+		}
+	}
+	if budget < minimums {
+		return plan, ErrBudgetTooLow
+	}
+	ordered := order(debts, strategy)
+	balances := make([]int64, len(ordered))
+	interest := make([]int64, len(ordered))
+	remaining := 0
+	for i, d := range ordered {
+		if d.Balance > 0 {
+			balances[i] = d.Balance
+			remaining++
+		}
+	}
+	month := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, start.Location())
+	for remaining > 0 {
+		if plan.Months == maxMonths {
+			return plan, ErrNeverPaidOff
+		}
+		plan.Months++
+		payments := make([]Payment, len(ordered))
+		left := budget
+		for i, d := range ordered {
+			payments[i] = Payment{DebtID: d.ID, Date: paymentDate(d, month)}
+			if balances[i] <= 0 {
+				continue
+			}
+			accrued := int64(math.Round(float64(balances[i]) * d.APR / 100 / 12))
+			balances[i] += accrued
+			interest[i] += accrued
+			payments[i].Interest = accrued
+			pay := d.MinimumPayment
+			if pay > balances[i] {
+				pay = balances[i]
+			}
+			payments[i].Payment = pay
+			balances[i] -= pay
+			left -= pay
+		}
+		for i := range ordered {
+			if left <= 0 {
+				break
+			}
+			pay := left
+			if pay > balances[i] {
+				pay = balances[i]
+			}
+			payments[i].Payment += pay
+			balances[i] -= pay
+			left -= pay
+		}
+		scheduled := Month{Month: month, Payments: []Payment{}}
+		for i, d := range ordered {
+			if payments[i].Payment == 0 && payments[i].Interest == 0 {
+				continue
+			}
+			payments[i].Balance = balances[i]
+			scheduled.Payments = append(scheduled.Payments, payments[i])
+			plan.TotalInterest += payments[i].Interest
+			plan.TotalPaid += payments[i].Payment
+			if balances[i] == 0 {
+				remaining--
+				if payments[i].Date.After(plan.PayoffDate) {
+					plan.PayoffDate = payments[i].Date
+				}
+				plan.Payoffs = append(plan.Payoffs, Payoff{DebtID: d.ID, Name: d.Name, PayoffDate: payments[i].Date, Interest: interest[i]})
+			}
+		}
+		plan.Schedule = append(plan.Schedule, scheduled)
+		month = month.AddDate(0, 1, 0)
+	}
+	return plan, nil
+}
@@ -0,0 +1,115 @@
+package planner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrder(t *testing.T) {
+	card := Debt{ID: uuid.New(), Name: "card", Balance: 3000, APR: 25}
+	loan := Debt{ID: uuid.New(), Name: "loan", Balance: 1000, APR: 5}
+	store := Debt{ID: uuid.New(), Name: "store", Balance: 1000, APR: 18}
+	testCases := []struct {
+		name     string
+		strategy string
+		want     []Debt
+	}{
+		{name: "snowball", strategy: Snowball, want: []Debt{store, loan, card}},
+		{name: "avalanche", strategy: Avalanche, want: []Debt{card, store, loan}},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, order([]Debt{card, loan, store}, tt.strategy))
+		})
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	small := Debt{ID: uuid.New(), Name: "small", Balance: 1000, APR: 5, MinimumPayment: 50}
+	costly := Debt{ID: uuid.New(), Name: "costly", Balance: 3000, APR: 25, MinimumPayment: 100}
+	testCases := []struct {
+		name     string
+		debts    []Debt
+		budget   int64
+		strategy string
+		check    func(t *testing.T, plan Plan, err error)
+	}{
+		{
+			name:     "snowball pays the smallest balance first",
+			debts:    []Debt{costly, small},
+			budget:   500,
+			strategy: Snowball,
+			check: func(t *testing.T, plan Plan, err error) {
+				require.NoError(t, err)
+				require.Len(t, plan.Payoffs, 2)
+				require.Equal(t, small.ID, plan.Payoffs[0].DebtID)
+				require.Equal(t, costly.ID, plan.Payoffs[1].DebtID)
+			},
+		},
+		{
+			name:     "avalanche pays the highest APR first",
+			debts:    []Debt{costly, small},
+			budget:   500,
+			strategy: Avalanche,
+			check: func(t *testing.T, plan Plan, err error) {
+				require.NoError(t, err)
+				require.Len(t, plan.Payoffs, 2)
+				require.Equal(t, costly.ID, plan.Payoffs[0].DebtID)
+				require.Equal(t, small.ID, plan.Payoffs[1].DebtID)
+				snowball, err := Simulate([]Debt{costly, small}, 500, Snowball, start)
+				require.NoError(t, err)
+				require.Less(t, plan.TotalInterest, snowball.TotalInterest)
+				require.Equal(t, plan.TotalPaid, 4000+plan.TotalInterest)
+			},
+		},
+		{
+			name:     "budget below the minimums",
+			debts:    []Debt{costly, small},
+			budget:   149,
+			strategy: Snowball,
+			check: func(t *testing.T, plan Plan, err error) {
+				require.ErrorIs(t, err, ErrBudgetTooLow)
+			},
+		},
+		{
+			name:     "minimum payment below the interest",
+			debts:    []Debt{{ID: uuid.New(), Name: "mortgage", Balance: 100000, APR: 24, MinimumPayment: 500}},
+			budget:   500,
+			strategy: Avalanche,
+			check: func(t *testing.T, plan Plan, err error) {
+				require.ErrorIs(t, err, ErrNeverPaidOff)
+				require.Equal(t, maxMonths, plan.Months)
+				require.Empty(t, plan.Payoffs)
+			},
+		},
+		{
+			name:     "nothing owed",
+			debts:    []Debt{{ID: uuid.New(), Name: "paid", Balance: 0, MinimumPayment: 100}},
+			budget:   0,
+			strategy: Snowball,
+			check: func(t *testing.T, plan Plan, err error) {
+				require.NoError(t, err)
+				require.Zero(t, plan.Months)
+				require.Empty(t, plan.Schedule)
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := Simulate(tt.debts, tt.budget, tt.strategy, start)
+			tt.check(t, plan, err)
+		})
+	}
+}
+
+func TestPaymentDate(t *testing.T) {
+	day := func(d int) *int { return &d }
+	february := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, february, paymentDate(Debt{}, february))
+	require.Equal(t, time.Date(2024, 2, 15, 0, 0, 0, 0, time.UTC), paymentDate(Debt{DueDay: day(15)}, february))
+	require.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), paymentDate(Debt{DueDay: day(31)}, february))
+}
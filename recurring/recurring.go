@@ -0,0 +1,229 @@
+package recurring
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/peternabil/go-api/models"
+)
+
+const (
+	Weekly  = "weekly"
+	Monthly = "monthly"
+	Yearly  = "yearly"
+
+	// amountTolerance is how far, as a fraction of the median, an amount may
+	// be from the others and still count as the same charge.
+	amountTolerance = 0.2
+	// minFit is the share of gaps between charges that must match the cadence.
+	minFit = 0.75
+)
+
+// cadence is a charging interval with the range of day gaps accepted for it.
+type cadence struct {
+	name       string
+	minGap     int
+	maxGap     int
+	minCharges int
+	perYear    float64
+}
+
+var cadences = []cadence{
+	{name: Weekly, minGap: 6, maxGap: 8, minCharges: 3, perYear: 52},
+	{name: Monthly, minGap: 27, maxGap: 33, minCharges: 3, perYear: 12},
+	{name: Yearly, minGap: 355, maxGap: 375, minCharges: 2, perYear: 1},
+}
+
+// addMonths moves date n months on, keeping its day of the month but
+// clamping it to the last day of shorter months.
+func addMonths(date time.Time, n int) time.Time {
+	first := time.Date(date.Year(), date.Month()+time.Month(n), 1, date.Hour(), date.Minute(), date.Second(), date.Nanosecond(), date.Location())
+	day := date.Day()
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// nth returns the date of the n-th charge after the one on date. Monthly and
+// yearly charges keep the day of the month of date.
+func nth(name string, date time.Time, n int) time.Time {
+	switch name {
+	case Weekly:
+		return date.AddDate(0, 0, 7*n)
+	case Yearly:
+		return addMonths(date, 12*n)
+	default:
+		return addMonths(date, n)
+	}
+}
+
+// Next returns the date of the charge after the one on date.
+func Next(name string, date time.Time) time.Time {
+	return nth(name, date, 1)
+}
+
+// Upcoming returns the first charge on or after now, starting from a charge
+// due on date.
+func Upcoming(name string, date, now time.Time) time.Time {
+	next := date
+	for n := 1; next.Before(now); n++ {
+		next = nth(name, date, n)
+	}
+	return next
+}
+
+// Dates returns the charges from the one on date onwards that fall between
+// from and to, both included.
+func Dates(name string, date, from, to time.Time) []time.Time {
+	dates := []time.Time{}
+	next := date
+	for n := 1; !next.After(to); n++ {
+		if !next.Before(from) {
+			dates = append(dates, next)
+		}
+		next = nth(name, date, n)
+	}
+	return dates
+}
+
+// Anchor returns the charge the series of a recurring definition is counted
+// from: the last one it was detected from, or its next one for definitions
+// saved without it.
+func Anchor(r models.Recurring) time.Time {
+	if !r.LastDate.IsZero() {
+		return r.LastDate
+	}
+	return r.NextDate
+}
+
+// Subscription is a charge found repeating at a regular cadence.
+type Subscription struct {
+	Pattern       string
+	Title         string
+	CategoryID    uuid.UUID
+	Cadence       string
+	Charges       int
+	AverageAmount int64
+	LastDate      time.Time
+	NextDate      time.Time
+	AnnualCost    int64
+}
+
+// Pattern normalizes a title so that charges whose titles differ only in
+// case, digits or punctuation, such as reference numbers, group together.
+func Pattern(title string) string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	return strings.Join(words, " ")
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+func days(from, to time.Time) int {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(math.Round(to.Sub(from).Hours() / 24))
+}
+
+// match returns the cadence the charges follow, if any. Charges must be in
+// date order.
+func match(charges []models.Transaction) (cadence, bool) {
+	gaps := []float64{}
+	for i := 1; i < len(charges); i++ {
+		gaps = append(gaps, float64(days(charges[i-1].CreatedAt, charges[i].CreatedAt)))
+	}
+	if len(gaps) == 0 {
+		return cadence{}, false
+	}
+	typical := median(gaps)
+	for _, c := range cadences {
+		if len(charges) < c.minCharges || typical < float64(c.minGap) || typical > float64(c.maxGap) {
+			continue
+		}
+		fit := 0
+		for _, gap := range gaps {
+			if gap >= float64(c.minGap) && gap <= float64(c.maxGap) {
+				fit++
+			}
+		}
+		if float64(fit) >= minFit*float64(len(gaps)) {
+			return c, true
+		}
+	}
+	return cadence{}, false
+}
+
+// Detect finds the expenses in history that repeat weekly, monthly or yearly
+// under a similar title and for a similar amount, with dates taken in loc.
+// The most expensive subscriptions come first.
+func Detect(history []models.Transaction, loc *time.Location) []Subscription {
+	groups := map[string][]models.Transaction{}
+	for _, t := range history {
+		if !t.Negative {
+			continue
+		}
+		pattern := Pattern(t.Title)
+		if pattern == "" {
+			continue
+		}
+		t.CreatedAt = t.CreatedAt.In(loc)
+		groups[pattern] = append(groups[pattern], t)
+	}
+	subscriptions := []Subscription{}
+	for pattern, group := range groups {
+		amounts := make([]float64, len(group))
+		for i, t := range group {
+			amounts[i] = float64(t.Amount)
+		}
+		typical := median(amounts)
+		charges := []models.Transaction{}
+		var total int64
+		for _, t := range group {
+			if math.Abs(float64(t.Amount)-typical) <= amountTolerance*typical {
+				charges = append(charges, t)
+				total += int64(t.Amount)
+			}
+		}
+		sort.Slice(charges, func(i, j int) bool {
+			return charges[i].CreatedAt.Before(charges[j].CreatedAt)
+		})
+		c, ok := match(charges)
+		if !ok {
+			continue
+		}
+		last := charges[len(charges)-1]
+		average := int64(math.Round(float64(total) / float64(len(charges))))
+		subscriptions = append(subscriptions, Subscription{
+			Pattern:       pattern,
+			Title:         last.Title,
+			CategoryID:    last.CategoryID,
+			Cadence:       c.name,
+			Charges:       len(charges),
+			AverageAmount: average,
+			LastDate:      last.CreatedAt,
+			NextDate:      Next(c.name, last.CreatedAt),
+			AnnualCost:    int64(math.Round(float64(average) * c.perYear)),
+		})
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		if subscriptions[i].AnnualCost != subscriptions[j].AnnualCost {
+			return subscriptions[i].AnnualCost > subscriptions[j].AnnualCost
+		}
+		return subscriptions[i].Pattern < subscriptions[j].Pattern
+	})
+	return subscriptions
+}
@@ -0,0 +1,143 @@
+package recurring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/peternabil/go-api/models"
+	"github.com/stretchr/testify/require"
+)
+
+func charges(title string, amount int, dates ...time.Time) []models.Transaction {
+	transactions := []models.Transaction{}
+	for _, date := range dates {
+		t := models.Transaction{ID: uuid.New(), Title: title, Amount: amount, Negative: true}
+		t.CreatedAt = date
+		transactions = append(transactions, t)
+	}
+	return transactions
+}
+
+func day(month time.Month, d int) time.Time {
+	return time.Date(2023, month, d, 12, 0, 0, 0, time.UTC)
+}
+
+func TestPattern(t *testing.T) {
+	require.Equal(t, "netflix com", Pattern("NETFLIX.COM #1234"))
+	require.Equal(t, "spotify", Pattern("Spotify 06/23"))
+	require.Equal(t, "", Pattern("#42"))
+}
+
+func TestDetect(t *testing.T) {
+	testCases := []struct {
+		name    string
+		history []models.Transaction
+		cadence string
+		charges int
+	}{
+		{
+			name:    "monthly",
+			history: charges("Streaming", 1500, day(1, 5), day(2, 5), day(3, 5), day(4, 5)),
+			cadence: Monthly,
+			charges: 4,
+		},
+		{
+			name:    "monthly around short months",
+			history: charges("Streaming", 1500, day(1, 31), day(2, 28), day(3, 31), day(4, 30)),
+			cadence: Monthly,
+			charges: 4,
+		},
+		{
+			name:    "weekly with one late charge",
+			history: charges("Gym", 1000, day(5, 1), day(5, 8), day(5, 15), day(5, 23), day(5, 30)),
+			cadence: Weekly,
+			charges: 5,
+		},
+		{
+			name:    "yearly",
+			history: charges("Domain", 1200, time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2022, 6, 3, 0, 0, 0, 0, time.UTC)),
+			cadence: Yearly,
+			charges: 2,
+		},
+		{
+			name:    "irregular intervals",
+			history: charges("Taxi", 2000, day(1, 2), day(1, 20), day(3, 1), day(3, 9), day(5, 30)),
+		},
+		{
+			name:    "mostly monthly with too many misses",
+			history: charges("Streaming", 1500, day(1, 5), day(2, 5), day(2, 20), day(3, 5), day(4, 20)),
+		},
+		{
+			name:    "too few charges",
+			history: charges("Streaming", 1500, day(1, 5), day(2, 5)),
+		},
+		{
+			name:    "amounts that differ too much",
+			history: append(charges("Shop", 1000, day(1, 5), day(3, 5)), charges("Shop", 5000, day(2, 5), day(4, 5))...),
+		},
+		{
+			name: "income is left out",
+			history: func() []models.Transaction {
+				history := charges("Salary", 500000, day(1, 25), day(2, 25), day(3, 25))
+				for i := range history {
+					history[i].Negative = false
+				}
+				return history
+			}(),
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			subscriptions := Detect(tt.history, time.UTC)
+			if tt.cadence == "" {
+				require.Empty(t, subscriptions)
+				return
+			}
+			require.Len(t, subscriptions, 1)
+			require.Equal(t, tt.cadence, subscriptions[0].Cadence)
+			require.Equal(t, tt.charges, subscriptions[0].Charges)
+		})
+	}
+}
+
+func TestDetectOutliers(t *testing.T) {
+	// a one-off charge under the same title does not break the series
+	history := charges("Streaming", 1500, day(1, 5), day(2, 5), day(3, 5))
+	history = append(history, charges("Streaming", 9900, day(2, 17))...)
+	subscriptions := Detect(history, time.UTC)
+	require.Len(t, subscriptions, 1)
+	require.Equal(t, 3, subscriptions[0].Charges)
+	require.Equal(t, int64(1500), subscriptions[0].AverageAmount)
+	require.Equal(t, int64(18000), subscriptions[0].AnnualCost)
+	require.Equal(t, day(4, 5), subscriptions[0].NextDate)
+}
+
+func TestUpcoming(t *testing.T) {
+	require.Equal(t, day(3, 5), Upcoming(Monthly, day(1, 5), day(3, 5)))
+	require.Equal(t, day(4, 5), Upcoming(Monthly, day(1, 5), day(3, 6)))
+	require.Equal(t, day(6, 1), Upcoming(Weekly, day(5, 4), day(5, 30)))
+	require.Equal(t, day(9, 1), Upcoming(Yearly, day(9, 1), day(1, 1)))
+}
+
+func TestMonthEnds(t *testing.T) {
+	january := time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC)
+	require.Equal(t, time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC), Next(Monthly, january))
+	// the series keeps the 31st after a short month instead of drifting
+	require.Equal(t, time.Date(2024, 3, 31, 9, 0, 0, 0, time.UTC), Upcoming(Monthly, january, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)))
+	require.Equal(t, []time.Time{
+		time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 4, 30, 9, 0, 0, 0, time.UTC),
+	}, Dates(Monthly, january, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 4, 30, 23, 0, 0, 0, time.UTC)))
+	leapDay := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC), Next(Yearly, leapDay))
+	require.Equal(t, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC), Upcoming(Yearly, leapDay, time.Date(2028, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestAnchor(t *testing.T) {
+	last := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	next := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, last, Anchor(models.Recurring{LastDate: last, NextDate: next}))
+	require.Equal(t, next, Anchor(models.Recurring{NextDate: next}))
+}
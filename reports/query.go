@@ -0,0 +1,185 @@
+package reports
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/peternabil/go-api/models"
+)
+
+// MaxDimensions caps how many dimensions a report can group by.
+const MaxDimensions = 3
+
+// dimension is a way of grouping transactions t. Columns are selected and
+// grouped on, the last one being what rows are ordered by, and Join brings in
+// any table they need.
+type dimension struct {
+	Columns []string
+	Join    string
+}
+
+// dimensions are the only groupings a report can use; nothing from the report
+// definition itself ever reaches the SQL text.
+var dimensions = map[string]dimension{
+	"category": {
+		Columns: []string{"CAST(c.id AS text) AS category_id", "c.name AS category"},
+		Join:    "LEFT JOIN categories c ON c.id = t.category_id",
+	},
+	"priority": {
+		Columns: []string{"CAST(p.id AS text) AS priority_id", "p.name AS priority"},
+		Join:    "LEFT JOIN priorities p ON p.id = t.priority_id",
+	},
+	// a transaction is counted once under each of its comma separated tags
+	"tag": {
+		Columns: []string{"tag.name AS tag"},
+		Join:    "LEFT JOIN LATERAL (SELECT DISTINCT trim(x) AS name FROM unnest(string_to_array(t.tags, ',')) x WHERE trim(x) <> '') tag ON true",
+	},
+	"month": {
+		Columns: []string{"date_trunc('month', t.created_at AT TIME ZONE @tz) AS month"},
+	},
+	// days of the week are numbered like time.Weekday, from 0 for Sunday
+	"weekday": {
+		Columns: []string{"CAST(extract(dow FROM t.created_at AT TIME ZONE @tz) AS int) AS weekday"},
+	},
+	"negative": {
+		Columns: []string{"t.negative AS negative"},
+	},
+}
+
+// measures are the only aggregates a report can compute, over the amount
+// SQL they are given.
+var measures = map[string]string{
+	"sum":     "coalesce(sum(%s), 0) AS sum",
+	"count":   "count(t.id) AS count",
+	"average": "CAST(round(avg(%s), 2) AS float8) AS average",
+}
+
+// signedAmount counts income up and expenses down, for reports that mix the
+// two.
+const signedAmount = "CASE WHEN t.negative THEN -t.amount ELSE t.amount END"
+
+// Split turns a comma separated list of names into its trimmed, non-empty
+// parts.
+func Split(list string) []string {
+	names := []string{}
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Validate checks that the report only uses known dimensions and measures,
+// each at most once, and that it has at least one measure.
+func Validate(report models.Report) error {
+	dims := Split(report.Dimensions)
+	if len(dims) > MaxDimensions {
+		return fmt.Errorf("a report can have at most %d dimensions", MaxDimensions)
+	}
+	seen := map[string]bool{}
+	for _, d := range dims {
+		if _, ok := dimensions[d]; !ok {
+			return fmt.Errorf("unknown dimension %q", d)
+		}
+		if seen[d] {
+			return fmt.Errorf("dimension %q is used twice", d)
+		}
+		seen[d] = true
+	}
+	ms := Split(report.Measures)
+	if len(ms) == 0 {
+		return fmt.Errorf("a report needs at least one measure")
+	}
+	for _, m := range ms {
+		if _, ok := measures[m]; !ok {
+			return fmt.Errorf("unknown measure %q", m)
+		}
+		if seen[m] {
+			return fmt.Errorf("measure %q is used twice", m)
+		}
+		seen[m] = true
+	}
+	if report.MinAmount != nil && report.MaxAmount != nil && *report.MinAmount > *report.MaxAmount {
+		return fmt.Errorf("MinAmount is above MaxAmount")
+	}
+	return nil
+}
+
+// Compile turns the report into a parameterized query over the user's
+// transactions between startDate and endDate. Months and weekdays are taken
+// in the time zone named tz. Rows come out ordered by the dimensions in the
+// order the report lists them. Unless the report filters on or groups by
+// sign, sums and averages are of income minus expenses.
+func Compile(report models.Report, id uuid.UUID, startDate, endDate time.Time, tz string) (string, []any, error) {
+	if err := Validate(report); err != nil {
+		return "", nil, err
+	}
+	// groups and order refer to columns by position, as every @tz in the
+	// query is bound as a separate parameter
+	columns, groups, joins, order := []string{}, []string{}, []string{}, []string{}
+	seen := map[string]bool{}
+	for _, name := range Split(report.Dimensions) {
+		seen[name] = true
+		d := dimensions[name]
+		for _, column := range d.Columns {
+			columns = append(columns, column)
+			groups = append(groups, fmt.Sprint(len(columns)))
+		}
+		if d.Join != "" {
+			joins = append(joins, d.Join)
+		}
+		order = append(order, fmt.Sprint(len(columns)))
+	}
+	// without a sign filter or dimension income and expenses land in the
+	// same rows, where only their net makes sense
+	amount := "t.amount"
+	if report.Negative == nil && !seen["negative"] {
+		amount = signedAmount
+	}
+	for _, name := range Split(report.Measures) {
+		if m := measures[name]; strings.Contains(m, "%s") {
+			columns = append(columns, fmt.Sprintf(m, amount))
+		} else {
+			columns = append(columns, m)
+		}
+	}
+	where := []string{"t.user_id = @user", "t.created_at BETWEEN @start AND @end", "t.deleted_at IS NULL"}
+	args := []any{sql.Named("user", id), sql.Named("start", startDate), sql.Named("end", endDate), sql.Named("tz", tz)}
+	if report.CategoryID != nil {
+		where = append(where, "t.category_id = @category")
+		args = append(args, sql.Named("category", *report.CategoryID))
+	}
+	if report.PriorityID != nil {
+		where = append(where, "t.priority_id = @priority")
+		args = append(args, sql.Named("priority", *report.PriorityID))
+	}
+	if report.Tag != "" {
+		where = append(where, "EXISTS (SELECT 1 FROM unnest(string_to_array(t.tags, ',')) x WHERE trim(x) = @tag)")
+		args = append(args, sql.Named("tag", strings.TrimSpace(report.Tag)))
+	}
+	if report.Negative != nil {
+		where = append(where, "t.negative = @negative")
+		args = append(args, sql.Named("negative", *report.Negative))
+	}
+	if report.MinAmount != nil {
+		where = append(where, "t.amount >= @min_amount")
+		args = append(args, sql.Named("min_amount", *report.MinAmount))
+	}
+	if report.MaxAmount != nil {
+		where = append(where, "t.amount <= @max_amount")
+		args = append(args, sql.Named("max_amount", *report.MaxAmount))
+	}
+	query := "SELECT " + strings.Join(columns, ", ") + " FROM transactions t"
+	for _, join := range joins {
+		query += " " + join
+	}
+	query += " WHERE " + strings.Join(where, " AND ")
+	if len(groups) > 0 {
+		query += " GROUP BY " + strings.Join(groups, ", ") + " ORDER BY " + strings.Join(order, ", ")
+	}
+	return query, args, nil
+}
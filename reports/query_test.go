@@ -0,0 +1,63 @@
+package reports
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/peternabil/go-api/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompile(t *testing.T) {
+	expenses := true
+	minAmount, maxAmount := 10, 5
+	testCases := []struct {
+		name     string
+		report   models.Report
+		contains []string
+		excludes []string
+		err      bool
+	}{
+		{
+			name:     "mixed signs are netted",
+			report:   models.Report{Dimensions: "month", Measures: "sum,average"},
+			contains: []string{"sum(" + signedAmount + ")", "avg(" + signedAmount + ")", "GROUP BY 1 ORDER BY 1"},
+		},
+		{
+			name:     "sign filter",
+			report:   models.Report{Dimensions: "category", Measures: "sum", Negative: &expenses},
+			contains: []string{"sum(t.amount)", "t.negative = @negative", "GROUP BY 1, 2 ORDER BY 2"},
+			excludes: []string{signedAmount},
+		},
+		{
+			name:     "sign dimension",
+			report:   models.Report{Dimensions: "negative, tag", Measures: "sum,count"},
+			contains: []string{"sum(t.amount)", "count(t.id)", "LEFT JOIN LATERAL"},
+			excludes: []string{signedAmount},
+		},
+		{name: "unknown dimension", report: models.Report{Dimensions: "account", Measures: "sum"}, err: true},
+		{name: "sql as measure", report: models.Report{Measures: "sum); DROP TABLE users; --"}, err: true},
+		{name: "repeated dimension", report: models.Report{Dimensions: "month,month", Measures: "sum"}, err: true},
+		{name: "no measure", report: models.Report{Dimensions: "month"}, err: true},
+		{name: "empty amount range", report: models.Report{Measures: "sum", MinAmount: &minAmount, MaxAmount: &maxAmount}, err: true},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := Compile(tt.report, uuid.New(), time.Now(), time.Now(), "UTC")
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NotEmpty(t, args)
+			for _, s := range tt.contains {
+				require.True(t, strings.Contains(query, s), "%q not in %s", s, query)
+			}
+			for _, s := range tt.excludes {
+				require.False(t, strings.Contains(query, s), "%q in %s", s, query)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package reports
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/peternabil/go-api/models"
+)
+
+const statementDateLayout = "2006-01-02"
+
+// Statement holds everything printed on a periodic account statement.
+type Statement struct {
+	User              models.User
+	StartDate         time.Time
+	EndDate           time.Time
+	OpeningBalance    int64
+	ExpenseCategories []models.SpendingCategory
+	IncomeCategories  []models.SpendingCategory
+	ExpensePriorities []models.SpendingPriority
+	IncomePriorities  []models.SpendingPriority
+	Transactions      []models.Transaction
+}
+
+// Income returns the sum of all positive transactions in the statement.
+func (s Statement) Income() int64 {
+	var total int64
+	for _, t := range s.Transactions {
+		if !t.Negative {
+			total += int64(t.Amount)
+		}
+	}
+	return total
+}
+
+// Expense returns the sum of all negative transactions in the statement.
+func (s Statement) Expense() int64 {
+	var total int64
+	for _, t := range s.Transactions {
+		if t.Negative {
+			total += int64(t.Amount)
+		}
+	}
+	return total
+}
+
+// ClosingBalance returns the balance at the end of the statement period.
+func (s Statement) ClosingBalance() int64 {
+	return s.OpeningBalance + s.Income() - s.Expense()
+}
+
+// WritePDF renders the statement as a PDF document to w.
+func (s Statement) WritePDF(w io.Writer) error {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetTitle("Statement", true)
+	pdf.SetCreator("smart-account", true)
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont("Arial", "I", 8)
+		pdf.CellFormat(0, 10, fmt.Sprintf("Page %d/{nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	pdf.AddPage()
+
+	pdf.SetFont("Arial", "B", 16)
+	pdf.CellFormat(0, 10, "Account Statement", "", 1, "L", false, 0, "")
+	pdf.SetFont("Arial", "", 10)
+	pdf.CellFormat(0, 6, tr(fmt.Sprintf("%s %s (%s)", s.User.FirstName, s.User.LastName, s.User.Email)), "", 1, "L", false, 0, "")
+	pdf.CellFormat(0, 6, fmt.Sprintf("Period: %s to %s", s.StartDate.Format(statementDateLayout), s.EndDate.Format(statementDateLayout)), "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+
+	section(pdf, "Summary")
+	row(pdf, tr, []float64{120, 60}, []string{"Opening balance", fmt.Sprint(s.OpeningBalance)}, false)
+	row(pdf, tr, []float64{120, 60}, []string{"Income", fmt.Sprint(s.Income())}, false)
+	row(pdf, tr, []float64{120, 60}, []string{"Expense", fmt.Sprint(s.Expense())}, false)
+	row(pdf, tr, []float64{120, 60}, []string{"Net", fmt.Sprint(s.Income() - s.Expense())}, false)
+	row(pdf, tr, []float64{120, 60}, []string{"Closing balance", fmt.Sprint(s.ClosingBalance())}, false)
+	pdf.Ln(4)
+
+	section(pdf, "Expenses by category")
+	categoryTable(pdf, tr, s.ExpenseCategories)
+	section(pdf, "Income by category")
+	categoryTable(pdf, tr, s.IncomeCategories)
+	section(pdf, "Expenses by priority")
+	priorityTable(pdf, tr, s.ExpensePriorities)
+	section(pdf, "Income by priority")
+	priorityTable(pdf, tr, s.IncomePriorities)
+
+	section(pdf, "Transactions")
+	widths := []float64{25, 55, 40, 30, 30}
+	row(pdf, tr, widths, []string{"Date", "Title", "Category", "Priority", "Amount"}, true)
+	for _, t := range s.Transactions {
+		amount := fmt.Sprint(t.Amount)
+		if t.Negative {
+			amount = "-" + amount
+		}
+		row(pdf, tr, widths, []string{t.CreatedAt.Format(statementDateLayout), t.Title, t.Category.Name, t.Priority.Name, amount}, false)
+	}
+
+	if pdf.Err() {
+		return pdf.Error()
+	}
+	return pdf.Output(w)
+}
+
+func section(pdf *gofpdf.Fpdf, title string) {
+	pdf.SetFont("Arial", "B", 12)
+	pdf.CellFormat(0, 8, title, "", 1, "L", false, 0, "")
+	pdf.SetFont("Arial", "", 10)
+}
+
+func row(pdf *gofpdf.Fpdf, tr func(string) string, widths []float64, cells []string, header bool) {
+	if header {
+		pdf.SetFont("Arial", "B", 10)
+		pdf.SetFillColor(230, 230, 230)
+	}
+	for i, cell := range cells {
+		align := "L"
+		if i == len(cells)-1 {
+			align = "R"
+		}
+		pdf.CellFormat(widths[i], 6, tr(cell), "1", 0, align, header, 0, "")
+	}
+	pdf.Ln(-1)
+	if header {
+		pdf.SetFont("Arial", "", 10)
+	}
+}
+
+func categoryTable(pdf *gofpdf.Fpdf, tr func(string) string, spendings []models.SpendingCategory) {
+	widths := []float64{120, 60}
+	row(pdf, tr, widths, []string{"Category", "Total"}, true)
+	for _, sp := range spendings {
+		row(pdf, tr, widths, []string{sp.Cname, fmt.Sprint(sp.Total)}, false)
+	}
+	pdf.Ln(4)
+}
+
+func priorityTable(pdf *gofpdf.Fpdf, tr func(string) string, spendings []models.SpendingPriority) {
+	widths := []float64{100, 20, 60}
+	row(pdf, tr, widths, []string{"Priority", "Level", "Total"}, true)
+	for _, sp := range spendings {
+		row(pdf, tr, widths, []string{sp.Pname, fmt.Sprint(sp.Level), fmt.Sprint(sp.Total)}, false)
+	}
+	pdf.Ln(4)
+}
@@ -17,26 +17,84 @@ type Store interface {
 	DeleteTransaction(transaction *models.Transaction) error
 	GetTransaction(transaction *models.Transaction) (models.Transaction, error)
 	GetTransactions(id uuid.UUID, page, pageSize int, count *int64) ([]models.Transaction, error)
+	GetTransactionsDateRange(id uuid.UUID, startDate, endDate time.Time) ([]models.Transaction, error)
+	GetBalance(id uuid.UUID, date time.Time) (int64, error)
+	GetAllTransactions(id uuid.UUID) ([]models.Transaction, error)
+	GetRecentTransactions(id uuid.UUID, since time.Time, limit int) ([]models.Transaction, error)
+	EditTransactions(transactions []models.Transaction) error
 
 	CreateCategory(category *models.Category) (models.Category, error)
 	EditCategory(category *models.Category) (models.Category, error)
 	DeleteCategory(category *models.Category) error
 	GetCategory(id uuid.UUID, category *models.Category) (models.Category, error)
 	GetCategories(id uuid.UUID) ([]models.Category, error)
+	MergeCategories(id uuid.UUID, source, target *models.Category) (models.MergeResult, error)
 
 	CreatePriority(priority *models.Priority) (models.Priority, error)
 	EditPriority(priority *models.Priority) (models.Priority, error)
 	DeletePriority(priority *models.Priority) error
 	GetPriority(id uuid.UUID, priority *models.Priority) (models.Priority, error)
 	GetPriorities(id uuid.UUID) ([]models.Priority, error)
+	MergePriorities(id uuid.UUID, source, target *models.Priority) (models.MergeResult, error)
 
-	SignUp(user *models.User) (models.User, error)
+	CreateRule(rule *models.Rule) (models.Rule, error)
+	EditRule(rule *models.Rule) (models.Rule, error)
+	DeleteRule(rule *models.Rule) error
+	GetRule(id uuid.UUID, rule *models.Rule) (models.Rule, error)
+	GetRules(id uuid.UUID) ([]models.Rule, error)
+
+	CreateBudget(budget *models.Budget) (models.Budget, error)
+	EditBudget(budget *models.Budget) (models.Budget, error)
+	DeleteBudget(budget *models.Budget) error
+	GetBudget(id uuid.UUID, budget *models.Budget) (models.Budget, error)
+	GetBudgets(id uuid.UUID) ([]models.Budget, error)
+
+	CreateGoal(goal *models.Goal) (models.Goal, error)
+	EditGoal(goal *models.Goal) (models.Goal, error)
+	DeleteGoal(goal *models.Goal) error
+	GetGoal(id uuid.UUID, goal *models.Goal) (models.Goal, error)
+	GetGoals(id uuid.UUID) ([]models.Goal, error)
+	GetGoalTransactions(id uuid.UUID, goal *models.Goal) ([]models.Transaction, error)
+
+	CreateDebt(debt *models.Debt) (models.Debt, error)
+	EditDebt(debt *models.Debt) (models.Debt, error)
+	DeleteDebt(debt *models.Debt) error
+	GetDebt(id uuid.UUID, debt *models.Debt) (models.Debt, error)
+	GetDebts(id uuid.UUID) ([]models.Debt, error)
+	GetDebtPayments(id uuid.UUID) (map[uuid.UUID]int64, error)
+
+	CreateRecurring(recurring *models.Recurring) (models.Recurring, error)
+	DeleteRecurring(recurring *models.Recurring) error
+	GetRecurring(id uuid.UUID, recurring *models.Recurring) (models.Recurring, error)
+	GetRecurrings(id uuid.UUID) ([]models.Recurring, error)
+
+	CreateReport(report *models.Report) (models.Report, error)
+	EditReport(report *models.Report) (models.Report, error)
+	DeleteReport(report *models.Report) error
+	GetReport(id uuid.UUID, report *models.Report) (models.Report, error)
+	GetReports(id uuid.UUID) ([]models.Report, error)
+	RunReport(id uuid.UUID, report models.Report, startDate, endDate time.Time) ([]map[string]any, error)
+
+	CreateEnvelopeEvent(event *models.EnvelopeEvent) (models.EnvelopeEvent, error)
+	GetEnvelopeEvents(id uuid.UUID, startDate, endDate time.Time) ([]models.EnvelopeEvent, error)
+
+	SignUp(user *models.User, categories []models.Category, priorities []models.Priority) (models.User, error)
+	SeedCategoriesAndPriorities(categories []models.Category, priorities []models.Priority) error
 	GetUser(user *models.User) (models.User, error)
 	GetUsers() ([]models.User, error)
+	EditUser(user *models.User) (models.User, error)
 	FindUser(email string) (models.User, error)
 
+	RebuildRollups(id uuid.UUID) error
+	CheckRollups(id uuid.UUID) ([]models.RollupMismatch, error)
+
 	GetTransactionsDateRangeGroupByDay(id uuid.UUID, startDate, endDate time.Time, negative bool) ([]models.Spending, error)
-	GetHighestSpendingCategory(id uuid.UUID, startDate, endDate time.Time, negative bool) ([]models.SpendingCategory, error)
+	GetHighestSpendingCategory(id uuid.UUID, startDate, endDate time.Time, negative bool, depth int) ([]models.SpendingCategory, error)
+	GetMonthlySpendingCategory(id uuid.UUID, startDate, endDate time.Time, negative bool) ([]models.SpendingCategory, error)
+	GetMonthlySpendingLevel(id uuid.UUID, startDate, endDate time.Time) ([]models.SpendingPriority, error)
+	GetSpendingPivot(id uuid.UUID, startDate, endDate time.Time, negative bool, rows, unit string) ([]models.PivotCell, error)
+	GetSpendingStats(id uuid.UUID, startDate, endDate time.Time, negative bool, rows string) ([]models.SpendingStats, error)
+	GetSpendingPatterns(id uuid.UUID, startDate, endDate time.Time, negative bool, categoryID, priorityID *uuid.UUID) ([]models.SpendingPattern, error)
 	GetHighestSpendingPriority(id uuid.UUID, startDate, endDate time.Time, negative bool) ([]models.SpendingPriority, error)
-	TotalSpending(id uuid.UUID, startDate, endDate time.Time, negative bool) ([]models.SpendingPriority, error)
+	TotalSpending(id uuid.UUID, startDate, endDate time.Time) (models.Summary, error)
 }
@@ -0,0 +1,155 @@
+package templates
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/peternabil/go-api/models"
+)
+
+// DefaultLocale is used whenever a translation is missing.
+const DefaultLocale = "en"
+
+// Localized maps a locale such as "en" or "ar" to a translated string.
+type Localized map[string]string
+
+// Get returns the translation for locale, falling back to DefaultLocale.
+func (l Localized) Get(locale string) string {
+	if s, ok := l[locale]; ok {
+		return s
+	}
+	return l[DefaultLocale]
+}
+
+type CategoryTemplate struct {
+	Name        Localized
+	Description Localized
+}
+
+type PriorityTemplate struct {
+	Name        Localized
+	Description Localized
+	Level       int
+}
+
+// Template is a starter set of categories and priorities a user can seed
+// their account with.
+type Template struct {
+	Key        string
+	Name       Localized
+	Categories []CategoryTemplate
+	Priorities []PriorityTemplate
+}
+
+// Build returns the template's rows for the user in the given locale.
+func (t Template) Build(userID uuid.UUID, locale string) ([]models.Category, []models.Priority) {
+	categories := make([]models.Category, 0, len(t.Categories))
+	for _, c := range t.Categories {
+		categories = append(categories, models.Category{Name: c.Name.Get(locale), Description: c.Description.Get(locale), UserID: userID})
+	}
+	priorities := make([]models.Priority, 0, len(t.Priorities))
+	for _, p := range t.Priorities {
+		priorities = append(priorities, models.Priority{Name: p.Name.Get(locale), Description: p.Description.Get(locale), Level: p.Level, UserID: userID})
+	}
+	return categories, priorities
+}
+
+// Find looks a template up by its key.
+func Find(key string) (Template, bool) {
+	t, ok := all[strings.ToLower(key)]
+	return t, ok
+}
+
+// All returns every template ordered by key.
+func All() []Template {
+	list := make([]Template, 0, len(all))
+	for _, t := range all {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Key < list[j].Key
+	})
+	return list
+}
+
+// Locale picks the first supported locale out of an explicit choice and an
+// Accept-Language header, defaulting to DefaultLocale.
+func Locale(explicit, acceptLanguage string) string {
+	candidates := []string{explicit}
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		candidates = append(candidates, strings.SplitN(strings.TrimSpace(part), ";", 2)[0])
+	}
+	for _, c := range candidates {
+		c = strings.ToLower(strings.SplitN(strings.SplitN(c, "-", 2)[0], "_", 2)[0])
+		for _, supported := range Locales {
+			if c == supported {
+				return c
+			}
+		}
+	}
+	return DefaultLocale
+}
+
+// Locales lists the locales every template is translated into.
+var Locales = []string{"en", "ar"}
+
+var all = map[string]Template{
+	"household": {
+		Key:  "household",
+		Name: Localized{"en": "Basic household", "ar": "منزل أساسي"},
+		Categories: []CategoryTemplate{
+			{Name: Localized{"en": "Groceries", "ar": "بقالة"}, Description: Localized{"en": "Food and household supplies", "ar": "طعام ومستلزمات منزلية"}},
+			{Name: Localized{"en": "Rent", "ar": "إيجار"}, Description: Localized{"en": "Rent or mortgage payments", "ar": "الإيجار أو أقساط الرهن"}},
+			{Name: Localized{"en": "Utilities", "ar": "مرافق"}, Description: Localized{"en": "Electricity, water, gas and internet", "ar": "كهرباء ومياه وغاز وإنترنت"}},
+			{Name: Localized{"en": "Transport", "ar": "مواصلات"}, Description: Localized{"en": "Fuel, fares and car costs", "ar": "وقود وأجرة وتكاليف السيارة"}},
+			{Name: Localized{"en": "Health", "ar": "صحة"}, Description: Localized{"en": "Doctors, medicine and insurance", "ar": "أطباء وأدوية وتأمين"}},
+			{Name: Localized{"en": "Entertainment", "ar": "ترفيه"}, Description: Localized{"en": "Eating out, outings and subscriptions", "ar": "مطاعم ونزهات واشتراكات"}},
+			{Name: Localized{"en": "Salary", "ar": "راتب"}, Description: Localized{"en": "Regular income", "ar": "دخل منتظم"}},
+		},
+		Priorities: []PriorityTemplate{
+			{Name: Localized{"en": "Essential", "ar": "ضروري"}, Description: Localized{"en": "Cannot be skipped", "ar": "لا يمكن الاستغناء عنه"}, Level: 10},
+			{Name: Localized{"en": "Important", "ar": "مهم"}, Description: Localized{"en": "Needed but flexible", "ar": "مطلوب لكنه مرن"}, Level: 7},
+			{Name: Localized{"en": "Nice to have", "ar": "كمالي"}, Description: Localized{"en": "Could be cut back", "ar": "يمكن تقليله"}, Level: 4},
+			{Name: Localized{"en": "Luxury", "ar": "رفاهية"}, Description: Localized{"en": "Pure treats", "ar": "متعة خالصة"}, Level: 1},
+		},
+	},
+	"student": {
+		Key:  "student",
+		Name: Localized{"en": "Student", "ar": "طالب"},
+		Categories: []CategoryTemplate{
+			{Name: Localized{"en": "Tuition", "ar": "رسوم دراسية"}, Description: Localized{"en": "Fees and courses", "ar": "رسوم ودورات"}},
+			{Name: Localized{"en": "Books and supplies", "ar": "كتب وأدوات"}, Description: Localized{"en": "Books, stationery and devices", "ar": "كتب وقرطاسية وأجهزة"}},
+			{Name: Localized{"en": "Food", "ar": "طعام"}, Description: Localized{"en": "Groceries and meals", "ar": "بقالة ووجبات"}},
+			{Name: Localized{"en": "Housing", "ar": "سكن"}, Description: Localized{"en": "Dorm or shared rent", "ar": "سكن جامعي أو إيجار مشترك"}},
+			{Name: Localized{"en": "Transport", "ar": "مواصلات"}, Description: Localized{"en": "Fares and passes", "ar": "أجرة واشتراكات"}},
+			{Name: Localized{"en": "Social", "ar": "اجتماعي"}, Description: Localized{"en": "Going out with friends", "ar": "الخروج مع الأصدقاء"}},
+			{Name: Localized{"en": "Allowance", "ar": "مصروف"}, Description: Localized{"en": "Allowance, grants and part-time pay", "ar": "مصروف ومنح وعمل جزئي"}},
+		},
+		Priorities: []PriorityTemplate{
+			{Name: Localized{"en": "Essential", "ar": "ضروري"}, Description: Localized{"en": "Cannot be skipped", "ar": "لا يمكن الاستغناء عنه"}, Level: 10},
+			{Name: Localized{"en": "Study", "ar": "دراسة"}, Description: Localized{"en": "Helps with studies", "ar": "يساعد في الدراسة"}, Level: 8},
+			{Name: Localized{"en": "Social", "ar": "اجتماعي"}, Description: Localized{"en": "Social life", "ar": "الحياة الاجتماعية"}, Level: 5},
+			{Name: Localized{"en": "Treat", "ar": "مكافأة"}, Description: Localized{"en": "Occasional treats", "ar": "مكافآت من حين لآخر"}, Level: 2},
+		},
+	},
+	"freelancer": {
+		Key:  "freelancer",
+		Name: Localized{"en": "Freelancer", "ar": "عمل حر"},
+		Categories: []CategoryTemplate{
+			{Name: Localized{"en": "Client income", "ar": "دخل العملاء"}, Description: Localized{"en": "Invoices paid by clients", "ar": "فواتير مدفوعة من العملاء"}},
+			{Name: Localized{"en": "Software and tools", "ar": "برمجيات وأدوات"}, Description: Localized{"en": "Subscriptions and licenses", "ar": "اشتراكات وتراخيص"}},
+			{Name: Localized{"en": "Equipment", "ar": "معدات"}, Description: Localized{"en": "Hardware and gear", "ar": "أجهزة ومعدات"}},
+			{Name: Localized{"en": "Taxes", "ar": "ضرائب"}, Description: Localized{"en": "Income tax and social security", "ar": "ضريبة الدخل والتأمينات"}},
+			{Name: Localized{"en": "Workspace", "ar": "مساحة عمل"}, Description: Localized{"en": "Office, coworking and internet", "ar": "مكتب ومساحة مشتركة وإنترنت"}},
+			{Name: Localized{"en": "Travel", "ar": "سفر"}, Description: Localized{"en": "Client visits and trips", "ar": "زيارات العملاء والرحلات"}},
+			{Name: Localized{"en": "Personal", "ar": "شخصي"}, Description: Localized{"en": "Personal spending", "ar": "مصروفات شخصية"}},
+		},
+		Priorities: []PriorityTemplate{
+			{Name: Localized{"en": "Business critical", "ar": "حيوي للعمل"}, Description: Localized{"en": "Work stops without it", "ar": "يتوقف العمل بدونه"}, Level: 10},
+			{Name: Localized{"en": "Tax deductible", "ar": "قابل للخصم الضريبي"}, Description: Localized{"en": "Business expense", "ar": "مصروف عمل"}, Level: 8},
+			{Name: Localized{"en": "Personal", "ar": "شخصي"}, Description: Localized{"en": "Personal needs", "ar": "احتياجات شخصية"}, Level: 5},
+			{Name: Localized{"en": "Optional", "ar": "اختياري"}, Description: Localized{"en": "Could be skipped", "ar": "يمكن الاستغناء عنه"}, Level: 2},
+		},
+	},
+}